@@ -0,0 +1,18 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+// Package ts provides regular time series, a key and a slice of values laid
+// out on a grid defined by a start time and a step.
+//
+// NaN marks a missing value: the statistics skip it, transforms usually keep
+// it and pairs or windows involving it are left out. Infinities on the other
+// hand are regular values, as produced by Div or Rate, and propagate through
+// the statistics following IEEE semantics. MeanFinite, MinFinite, MaxFinite
+// and StdDevFinite skip them as well, as do ZScore and MinMaxScale which keep
+// them unchanged, and ReplaceInf maps them to another value, such as NaN to
+// have them treated as missing everywhere.
+//
+// The renderings can't place infinities on a scale, so they are scaled on the
+// finite values: Histogram counts infinities in its first or last bin,
+// Sparkline draws them as the lowest or highest block and the charts leave a
+// gap as they do for NaN values.
+package ts
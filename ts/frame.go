@@ -0,0 +1,111 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"time"
+)
+
+// Frame holds the data of several series sharing the same time axis as
+// columns named by their keys.
+type Frame struct {
+	start   time.Time
+	step    time.Duration
+	length  int
+	keys    []string
+	columns map[string][]float64
+	fillers map[string]float64
+}
+
+func NewFrame() *Frame {
+	return &Frame{
+		columns: make(map[string][]float64),
+		fillers: make(map[string]float64),
+	}
+}
+
+func (f *Frame) Start() time.Time {
+	return f.start
+}
+func (f *Frame) Step() time.Duration {
+	return f.step
+}
+func (f *Frame) Len() int {
+	return f.length
+}
+
+// Keys returns the keys of the columns in the order they were added.
+func (f *Frame) Keys() []string {
+	keys := make([]string, len(f.keys))
+	copy(keys, f.keys)
+	return keys
+}
+
+// AddSeries adds a copy of the data and filler of ts as a column. The first series sets
+// the time axis of the frame that the following ones must match, see Align to
+// bring two series onto the same one.
+func (f *Frame) AddSeries(ts *TimeSeries) error {
+	if _, ok := f.columns[ts.key]; ok {
+		return fmt.Errorf("column '%s' already exists", ts.key)
+	}
+	if len(f.keys) == 0 {
+		f.start = ts.start
+		f.step = ts.step
+		f.length = len(ts.data)
+	} else if !ts.start.Equal(f.start) || ts.step != f.step || len(ts.data) != f.length {
+		return fmt.Errorf("series '%s' from %v to %v by %v doesn't match the frame from %v to %v by %v",
+			ts.key, ts.start, ts.End(), ts.step, f.start, f.end(), f.step)
+	}
+
+	f.keys = append(f.keys, ts.key)
+	f.columns[ts.key] = ts.Data()
+	f.fillers[ts.key] = ts.filler
+	return nil
+}
+
+func (f *Frame) end() time.Time {
+	return f.start.Add(time.Duration(f.length) * f.step)
+}
+
+// Column returns a copy of the column as a series with the filler of the
+// series it was added from.
+func (f *Frame) Column(key string) (*TimeSeries, bool) {
+	data, ok := f.columns[key]
+	if !ok {
+		return nil, false
+	}
+	ts := &TimeSeries{
+		key:    key,
+		start:  f.start,
+		step:   f.step,
+		data:   make([]float64, len(data)),
+		filler: f.fillers[key],
+	}
+	copy(ts.data, data)
+	return ts, true
+}
+
+// Rows returns an iterator over the rows of the frame.
+func (f *Frame) Rows() *RowIterator {
+	return &RowIterator{frame: f}
+}
+
+type RowIterator struct {
+	index int
+	frame *Frame
+}
+
+// Next returns the time of the next row and its values by column key.
+func (it *RowIterator) Next() (t time.Time, row map[string]float64, ok bool) {
+	if it.index >= it.frame.length {
+		return time.Time{}, nil, false
+	}
+	t = it.frame.start.Add(time.Duration(it.index) * it.frame.step)
+	row = make(map[string]float64, len(it.frame.keys))
+	for _, key := range it.frame.keys {
+		row[key] = it.frame.columns[key][it.index]
+	}
+	it.index++
+	return t, row, true
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFrame(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("cpu", start, step, []float64{1, 2, 3})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("mem", start, step, []float64{4, NaN, 6})
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("disk", start.Add(step), step, []float64{7, 8, 9})
+	checkErr(t, err)
+
+	f := NewFrame()
+	checkErr(t, f.AddSeries(ts0))
+	checkErr(t, f.AddSeries(ts1))
+	if err := f.AddSeries(ts2); err == nil {
+		t.Errorf("FAIL(AddSeries): expected error for a different start")
+	}
+	if err := f.AddSeries(ts0); err == nil {
+		t.Errorf("FAIL(AddSeries): expected error for a duplicate key")
+	}
+	if fmt.Sprint(f.Keys()) != "[cpu mem]" {
+		t.Errorf("FAIL(keys): got: '%v', expected '%v'", f.Keys(), []string{"cpu", "mem"})
+	}
+
+	ts0.SetAt(start, 42)
+	got, ok := f.Column("cpu")
+	if !ok {
+		t.Fatalf("FAIL(Column): missing column")
+	}
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "cpu",
+		start: start,
+		step:  step,
+		data:  []float64{1, 2, 3},
+	})
+	if _, ok := f.Column("disk"); ok {
+		t.Errorf("FAIL(Column): unexpected column")
+	}
+
+	exp := []map[string]float64{
+		{"cpu": 1, "mem": 4},
+		{"cpu": 2, "mem": NaN},
+		{"cpu": 3, "mem": 6},
+	}
+	var rows int
+	it := f.Rows()
+	for ti, row, ok := it.Next(); ok; ti, row, ok = it.Next() {
+		checkTime(t, "row", ti, start.Add(time.Duration(rows)*step))
+		for key, v := range exp[rows] {
+			checkFloat(t, key, row[key], v)
+		}
+		rows++
+	}
+	if rows != len(exp) {
+		t.Errorf("FAIL(rows): got: '%d', expected '%d'", rows, len(exp))
+	}
+}
+
+func TestFrameColumnFiller(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("cpu", start, step, []float64{7})
+	checkErr(t, err)
+	ts0.SetFiller(NaN, false)
+	ts1, err := NewTimeSeriesOfData("mem", start, step, []float64{4})
+	checkErr(t, err)
+	ts1.SetFiller(0, false)
+
+	f := NewFrame()
+	checkErr(t, f.AddSeries(ts0))
+	checkErr(t, f.AddSeries(ts1))
+
+	cpu, _ := f.Column("cpu")
+	checkFloat(t, "cpu filler", cpu.Filler(), NaN)
+	cpu.ExtendBy(step)
+	checkData(t, cpu.data, []float64{7, NaN})
+
+	mem, _ := f.Column("mem")
+	checkFloat(t, "mem filler", mem.Filler(), 0)
+	checkErr(t, mem.Verify())
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// SparseSeries holds values at arbitrary times, kept sorted by time, such as
+// events that are only later bucketed onto the grid of a TimeSeries.
+type SparseSeries struct {
+	key   string
+	times []time.Time
+	data  []float64
+}
+
+func NewSparseSeries(key string) *SparseSeries {
+	return &SparseSeries{key: key}
+}
+
+func (ss *SparseSeries) Key() string {
+	return ss.key
+}
+func (ss *SparseSeries) Len() int {
+	return len(ss.times)
+}
+func (ss *SparseSeries) Times() []time.Time {
+	times := make([]time.Time, len(ss.times))
+	copy(times, ss.times)
+	return times
+}
+func (ss *SparseSeries) Data() []float64 {
+	data := make([]float64, len(ss.data))
+	copy(data, ss.data)
+	return data
+}
+
+// search returns the index of the first time not before t.
+func (ss *SparseSeries) search(t time.Time) int {
+	return sort.Search(len(ss.times), func(i int) bool {
+		return !ss.times[i].Before(t)
+	})
+}
+
+// GetAt returns the value recorded at exactly t.
+func (ss *SparseSeries) GetAt(t time.Time) (float64, bool) {
+	i := ss.search(t)
+	if i == len(ss.times) || !ss.times[i].Equal(t) {
+		return math.NaN(), false
+	}
+	return ss.data[i], true
+}
+
+// InsertAt records value at t, replacing the value already recorded at t.
+func (ss *SparseSeries) InsertAt(t time.Time, value float64) {
+	i := ss.search(t)
+	if i < len(ss.times) && ss.times[i].Equal(t) {
+		ss.data[i] = value
+		return
+	}
+
+	ss.times = append(ss.times, time.Time{})
+	ss.data = append(ss.data, 0)
+	copy(ss.times[i+1:], ss.times[i:])
+	copy(ss.data[i+1:], ss.data[i:])
+	ss.times[i] = t
+	ss.data[i] = value
+}
+
+// ToRegular buckets the values onto a grid of step starting at the first time
+// truncated to step, each point aggregating the non NaN values within its
+// step. Empty buckets are NaN.
+func (ss *SparseSeries) ToRegular(step time.Duration, agg Aggregator) (*TimeSeries, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step %v must be positive", step)
+	}
+	if len(ss.times) == 0 {
+		return nil, errors.New("no values to bucket")
+	}
+
+	start := ss.times[0].Truncate(step)
+	size := int(ss.times[len(ss.times)-1].Sub(start)/step) + 1
+	ts := &TimeSeries{
+		key:    ss.key,
+		start:  start,
+		step:   step,
+		data:   make([]float64, size),
+		filler: math.NaN(),
+	}
+
+	bucket := []float64{}
+	j := 0
+	for i := range ts.data {
+		end := start.Add(time.Duration(i+1) * step)
+		bucket = bucket[:0]
+		for ; j < len(ss.times) && ss.times[j].Before(end); j++ {
+			if v := ss.data[j]; !math.IsNaN(v) {
+				bucket = append(bucket, v)
+			}
+		}
+		if len(bucket) == 0 {
+			ts.data[i] = math.NaN()
+			continue
+		}
+		ts.data[i] = agg.Aggregate(bucket)
+	}
+	return ts, nil
+}
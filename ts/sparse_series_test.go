@@ -0,0 +1,55 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSparseSeries(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ss := NewSparseSeries("events")
+	ss.InsertAt(start.Add(150*time.Second), 4)
+	ss.InsertAt(start.Add(10*time.Second), 1)
+	ss.InsertAt(start.Add(40*time.Second), 2)
+	ss.InsertAt(start.Add(20*time.Second), NaN)
+	ss.InsertAt(start.Add(40*time.Second), 3)
+
+	if ss.Len() != 4 {
+		t.Errorf("FAIL(len): got: '%d', expected '%d'", ss.Len(), 4)
+	}
+	checkData(t, ss.Data(), []float64{1, NaN, 3, 4})
+	for i, exp := range []time.Duration{10, 20, 40, 150} {
+		checkTime(t, fmt.Sprintf("time %d", i), ss.Times()[i], start.Add(exp*time.Second))
+	}
+
+	if v, ok := ss.GetAt(start.Add(40 * time.Second)); !ok || v != 3 {
+		t.Errorf("FAIL(GetAt): got: '%f', '%v', expected '%f'", v, ok, 3.0)
+	}
+	if _, ok := ss.GetAt(start.Add(30 * time.Second)); ok {
+		t.Errorf("FAIL(GetAt): expected no value between events")
+	}
+
+	got, err := ss.ToRegular(step, &SumAggregator{})
+	checkErr(t, err)
+	exp := &TimeSeries{
+		key:   "events",
+		start: start,
+		step:  step,
+		data:  []float64{4, NaN, 4},
+	}
+	fmt.Printf("%s\n%s\n\n", got, exp)
+	checkTimeSeries(t, got, exp)
+	checkErr(t, got.Verify())
+
+	if _, err := NewSparseSeries("empty").ToRegular(step, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(empty): expected error without values")
+	}
+	if _, err := ss.ToRegular(0, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(step): expected error for a zero step")
+	}
+}
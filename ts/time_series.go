@@ -14,8 +14,8 @@ import (
 // and actually all other NewTimeSeries... call this one.
 func NewTimeSeries(key string, start, end time.Time, step time.Duration, values ...float64) (*TimeSeries, error) {
 
-	if int(step) == 0 {
-		return nil, fmt.Errorf("step can't be 0")
+	if step <= 0 {
+		return nil, fmt.Errorf("step %v must be positive", step)
 	}
 
 	singleValue := false
@@ -46,13 +46,17 @@ func NewTimeSeries(key string, start, end time.Time, step time.Duration, values
 	if ts.start.After(end) {
 		return nil, fmt.Errorf("start time %v can't be after end %v time", start, end)
 	}
-	if !ts.start.Equal(end) {
-		if int64(ts.step) == 0 {
-			return nil, fmt.Errorf("step size can't be 0 if start != end time")
-		}
+
+	// Sub saturates for ranges over ~292 years and int may be 32 bits wide.
+	span := end.Sub(ts.start)
+	if !ts.start.Add(span).Equal(end) {
+		return nil, fmt.Errorf("range from %v to %v is too long", start, end)
+	}
+	if size := span / ts.step; int64(size) > int64(maxInt) {
+		return nil, fmt.Errorf("range from %v to %v holds too many steps of %v", start, end, step)
 	}
 
-	size := int(end.Sub(ts.start) / ts.step)
+	size := int(span / ts.step)
 	ts.data = make([]float64, size)
 
 	for i, _ := range ts.data {
@@ -68,11 +72,32 @@ func NewTimeSeries(key string, start, end time.Time, step time.Duration, values
 	return ts, nil
 }
 
+const maxInt = int(^uint(0) >> 1)
+
+// NewSnappedTimeSeries is like NewTimeSeries but truncates the start down to a
+// multiple of step, moving the end by the same amount.
+func NewSnappedTimeSeries(key string, start, end time.Time, step time.Duration, values ...float64) (*TimeSeries, error) {
+	if start.IsZero() {
+		start = time.Now()
+	}
+	snapped := start.Truncate(step)
+	if !end.IsZero() {
+		end = end.Add(snapped.Sub(start))
+	}
+	return NewTimeSeries(key, snapped, end, step, values...)
+}
+
 func NewTimeSeriesOfTimeRange(key string, start, end time.Time, step time.Duration, filler float64) (*TimeSeries, error) {
-	return NewTimeSeries(key, start, end.Add(step), step, filler)
+	return NewTimeSeries(key, start, end, step, filler)
 }
 
 func NewTimeSeriesOfLength(key string, start time.Time, step time.Duration, length int, filler float64) (*TimeSeries, error) {
+	if length < 0 {
+		return nil, fmt.Errorf("length %d can't be negative", length)
+	}
+	if step > 0 && int64(length) > int64(math.MaxInt64/step) {
+		return nil, fmt.Errorf("length %d of %v steps is too long", length, step)
+	}
 	return NewTimeSeries(key, start, start.Add(time.Duration(length)*step), step, filler)
 }
 
@@ -80,12 +105,100 @@ func NewTimeSeriesOfData(key string, start time.Time, step time.Duration, data [
 	return NewTimeSeries(key, start, time.Time{}, step, data...)
 }
 
+// MustTimeSeriesOfData is like NewTimeSeriesOfData but panics if the series
+// can't be created, for use with known valid arguments such as in tests and
+// package level variables.
+func MustTimeSeriesOfData(key string, start time.Time, step time.Duration, data []float64) *TimeSeries {
+	ts, err := NewTimeSeriesOfData(key, start, step, data)
+	if err != nil {
+		panic(err)
+	}
+	return ts
+}
+
+// NewRingTimeSeries returns an empty series holding at most capacity points.
+// Once full, extending the series drops the oldest points and advances the
+// start. The bound belongs to that series only: copies, derived series and
+// encoded series are unbounded. It panics if step isn't valid or capacity
+// isn't positive.
+func NewRingTimeSeries(key string, start time.Time, step time.Duration, capacity int) *TimeSeries {
+	if capacity < 1 {
+		panic(fmt.Sprintf("capacity %d must be positive", capacity))
+	}
+	ts, err := NewTimeSeriesOfData(key, start, step, []float64{})
+	if err != nil {
+		panic(err)
+	}
+	ts.capacity = capacity
+	return ts
+}
+
+// NewTimeSeriesFromSamples places each value onto the grid point closest to
+// its time, later samples overwriting earlier ones on the same point. The
+// series ends with the last filled point and the points without samples are
+// NaN.
+func NewTimeSeriesFromSamples(key string, start time.Time, step time.Duration, times []time.Time, values []float64) (*TimeSeries, error) {
+	if len(times) != len(values) {
+		return nil, fmt.Errorf("%d times != %d values", len(times), len(values))
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("step %v must be positive", step)
+	}
+
+	indexes := make([]int, len(times))
+	size := 0
+	for i, t := range times {
+		indexes[i] = nearestIndex(start, step, t)
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("sample at %v is before start %v", t, start)
+		}
+		if indexes[i] >= size {
+			size = indexes[i] + 1
+		}
+	}
+
+	ts, err := NewTimeSeriesOfLength(key, start, step, size, math.NaN())
+	if err != nil {
+		return nil, err
+	}
+	for i, index := range indexes {
+		ts.data[index] = values[i]
+	}
+	return ts, nil
+}
+
+// NewTimeSeriesFromTimedData is like NewTimeSeriesFromSamples starting at the
+// earliest time, so that the intervals without data are left as NaN gaps.
+func NewTimeSeriesFromTimedData(key string, step time.Duration, times []time.Time, values []float64) (*TimeSeries, error) {
+	if len(times) == 0 {
+		return nil, fmt.Errorf("no times to infer the start from")
+	}
+	start := times[0]
+	for _, t := range times[1:] {
+		if t.Before(start) {
+			start = t
+		}
+	}
+	return NewTimeSeriesFromSamples(key, start, step, times, values)
+}
+
 type TimeSeries struct {
 	key    string
 	start  time.Time
 	step   time.Duration
 	data   []float64
 	filler float64
+
+	// capacity bounds the number of points when positive. It isn't carried
+	// by Copy nor by the encodings, see NewRingTimeSeries.
+	capacity int
+
+	// weights is nil until a weight is set, see SetWeightAt.
+	weights []float64
+
+	// version is bumped by the methods mutating the series in place so that
+	// derived results can be invalidated, see CachedSeries.
+	version uint64
 }
 
 func (ts *TimeSeries) Key() string {
@@ -93,16 +206,65 @@ func (ts *TimeSeries) Key() string {
 }
 func (ts *TimeSeries) SetKey(key string) {
 	ts.key = key
+	ts.version++
+}
+
+// WithKey returns a copy named key, leaving the receiver untouched.
+func (ts *TimeSeries) WithKey(key string) *TimeSeries {
+	wts := ts.Copy()
+	wts.key = key
+	return wts
 }
+
 func (ts *TimeSeries) Start() time.Time {
 	return ts.start
 }
 func (ts *TimeSeries) End() time.Time {
-	return ts.start.Add(time.Duration(len(ts.data)) * ts.step)
+	return ts.TimeAt(len(ts.data))
+}
+
+// TimeAt returns the grid time of the point at index i. Indexes outside the
+// data are extrapolated along the grid.
+func (ts *TimeSeries) TimeAt(i int) time.Time {
+	return ts.start.Add(time.Duration(i) * ts.step)
 }
+
+// Times returns the grid time of each point, in the order of Data.
+func (ts *TimeSeries) Times() []time.Time {
+	times := make([]time.Time, len(ts.data))
+	for i := range times {
+		times[i] = ts.TimeAt(i)
+	}
+	return times
+}
+
 func (ts *TimeSeries) Step() time.Duration {
 	return ts.step
 }
+func (ts *TimeSeries) Len() int {
+	return len(ts.data)
+}
+func (ts *TimeSeries) IsEmpty() bool {
+	return len(ts.data) == 0
+}
+func (ts *TimeSeries) Filler() float64 {
+	return ts.filler
+}
+
+// SetFiller changes the value used to pad the series. When rewrite is set, the
+// points equal to the previous filler, NaN included, are changed as well.
+func (ts *TimeSeries) SetFiller(filler float64, rewrite bool) {
+	if rewrite {
+		wasNaN := math.IsNaN(ts.filler)
+		for i, v := range ts.data {
+			if v == ts.filler || wasNaN && math.IsNaN(v) {
+				ts.data[i] = filler
+			}
+		}
+	}
+	ts.filler = filler
+	ts.version++
+}
 func (ts *TimeSeries) Data() []float64 {
 	data := make([]float64, len(ts.data))
 	for i, v := range ts.data {
@@ -117,30 +279,112 @@ func (ts *TimeSeries) Copy() *TimeSeries {
 		step:   ts.step,
 		data:   ts.Data(),
 		filler: ts.filler,
+
+		weights: copyWeights(ts.weights),
 	}
 	return nts
 }
 
+// Verify checks the invariants of the series, such as after decoding it.
+func (ts *TimeSeries) Verify() error {
+	if ts.step <= 0 {
+		return fmt.Errorf("step %v must be positive", ts.step)
+	}
+	if ts.start.IsZero() {
+		return fmt.Errorf("start time can't be zero")
+	}
+	if ts.data == nil {
+		return fmt.Errorf("data can't be nil")
+	}
+	if ts.weights != nil && len(ts.weights) != len(ts.data) {
+		return fmt.Errorf("%d weights != %d points", len(ts.weights), len(ts.data))
+	}
+	if size := ts.End().Sub(ts.start) / ts.step; int(size) != len(ts.data) {
+		return fmt.Errorf("end %v doesn't match the %d points from %v", ts.End(), len(ts.data), ts.start)
+	}
+	return nil
+}
+
+// ExtendTo pads the series with the filler so that it covers t, after which
+// End is the first grid time strictly after t.
 func (ts *TimeSeries) ExtendTo(t time.Time) {
-	end := ts.End()
-	if t.Before(end) {
+	if t.Before(ts.End()) {
 		return
 	}
-	t = t.Add(ts.step)
-	points := t.Sub(end) / ts.step
+	size := int(t.Sub(ts.start)/ts.step) + 1
 
-	for i := 0; i < int(points); i++ {
-		ts.data = append(ts.data, ts.filler)
-	}
+	ts.grow(size - len(ts.data))
+	ts.padWeights()
+	ts.bound()
+	ts.version++
 }
 func (ts *TimeSeries) ExtendBy(d time.Duration) {
-	points := d / ts.step
-	for i := 0; i < int(points); i++ {
+	ts.grow(int(d / ts.step))
+	ts.padWeights()
+	ts.bound()
+	ts.version++
+}
+
+// grow appends n filler points. A ring series skips the points that bound
+// would drop right away, so that a large gap never allocates more than its
+// capacity.
+func (ts *TimeSeries) grow(n int) {
+	if ts.capacity > 0 && n > ts.capacity {
+		ts.start = ts.TimeAt(len(ts.data) + n - ts.capacity)
+		ts.data = ts.data[:0]
+		if ts.weights != nil {
+			ts.weights = ts.weights[:0]
+		}
+		n = ts.capacity
+	}
+	for i := 0; i < n; i++ {
 		ts.data = append(ts.data, ts.filler)
 	}
 }
 func (ts *TimeSeries) ExtendWith(data ...float64) {
 	ts.data = append(ts.data, data...)
+	ts.padWeights()
+	ts.bound()
+	ts.version++
+}
+
+// Push appends a single value, see ExtendWith.
+func (ts *TimeSeries) Push(value float64) {
+	ts.ExtendWith(value)
+}
+
+// Compact drops the trailing points equal to the filler, NaN included, and
+// reallocates the data to its exact length so that the capacity left over by
+// the Extend methods is released.
+func (ts *TimeSeries) Compact() {
+	isNaN := math.IsNaN(ts.filler)
+	size := len(ts.data)
+	for size > 0 && (ts.data[size-1] == ts.filler || isNaN && math.IsNaN(ts.data[size-1])) {
+		size--
+	}
+
+	data := make([]float64, size)
+	copy(data, ts.data)
+	ts.data = data
+	if ts.weights != nil {
+		ts.weights = copyWeights(ts.weights[:size])
+	}
+	ts.version++
+}
+
+// bound drops the oldest points of a ring series over its capacity.
+func (ts *TimeSeries) bound() {
+	if ts.capacity <= 0 || len(ts.data) <= ts.capacity {
+		return
+	}
+	drop := len(ts.data) - ts.capacity
+	copy(ts.data, ts.data[drop:])
+	ts.data = ts.data[:ts.capacity]
+	if ts.weights != nil {
+		copy(ts.weights, ts.weights[drop:])
+		ts.weights = ts.weights[:ts.capacity]
+	}
+	ts.start = ts.start.Add(time.Duration(drop) * ts.step)
 }
 
 func (ts *TimeSeries) index(t time.Time) int {
@@ -148,8 +392,7 @@ func (ts *TimeSeries) index(t time.Time) int {
 		return -1
 	}
 
-	end := ts.start.Add(time.Duration(len(ts.data)-1) * ts.step)
-	if t.After(end) {
+	if !t.Before(ts.End()) {
 		return -1
 	}
 
@@ -166,6 +409,47 @@ func (ts *TimeSeries) GetAt(t time.Time) (float64, bool) {
 	return ts.data[index], true
 }
 
+// GetAtOr returns the value at t, or def if t is outside the series or the
+// value is NaN.
+func (ts *TimeSeries) GetAtOr(t time.Time, def float64) float64 {
+	v, ok := ts.GetAt(t)
+	if !ok || math.IsNaN(v) {
+		return def
+	}
+	return v
+}
+
+// GetAtOrFiller is like GetAtOr with the filler as default.
+func (ts *TimeSeries) GetAtOrFiller(t time.Time) float64 {
+	return ts.GetAtOr(t, ts.filler)
+}
+
+// GetAtNearest returns the value of the grid point closest to t along with the
+// time of that grid point, so that callers can detect misaligned queries.
+func (ts *TimeSeries) GetAtNearest(t time.Time) (time.Time, float64, bool) {
+	index := nearestIndex(ts.start, ts.step, t)
+
+	nearest := ts.TimeAt(index)
+	if index < 0 || index >= len(ts.data) {
+		return nearest, math.NaN(), false
+	}
+	return nearest, ts.data[index], true
+}
+
+// nearestIndex returns the index of the grid point closest to t, which may be
+// negative or past the end of a series.
+func nearestIndex(start time.Time, step time.Duration, t time.Time) int {
+	distance := t.Sub(start)
+	index := distance / step
+	remainder := distance % step
+	if remainder*2 >= step {
+		index++
+	} else if remainder*2 < -step {
+		index--
+	}
+	return int(index)
+}
+
 func (ts *TimeSeries) SetAt(t time.Time, value float64) bool {
 	index := ts.index(t)
 	if index == -1 {
@@ -173,6 +457,7 @@ func (ts *TimeSeries) SetAt(t time.Time, value float64) bool {
 	}
 
 	ts.data[index] = value
+	ts.version++
 	return true
 }
 
@@ -180,6 +465,32 @@ func (ts *TimeSeries) IsEqualStep(other *TimeSeries) bool {
 	return ts.step == other.step
 }
 
+// Equal reports whether both series have the same key, start, step and data,
+// NaN values being equal to each other.
+func (ts *TimeSeries) Equal(other *TimeSeries) bool {
+	return ts.EqualApprox(other, 0)
+}
+
+// EqualApprox is like Equal but accepts values that differ by at most eps.
+func (ts *TimeSeries) EqualApprox(other *TimeSeries, eps float64) bool {
+	if ts.key != other.key || !ts.start.Equal(other.start) || ts.step != other.step {
+		return false
+	}
+	if len(ts.data) != len(other.data) {
+		return false
+	}
+	for i, v := range ts.data {
+		w := other.data[i]
+		if v == w || math.IsNaN(v) && math.IsNaN(w) {
+			continue
+		}
+		if !(math.Abs(v-w) <= eps) {
+			return false
+		}
+	}
+	return true
+}
+
 func (ts *TimeSeries) Transform(transform Transform) *TimeSeries {
 	tts := ts.Copy()
 	tts.key = transform.Name() + "(" + ts.key + ")"
@@ -188,33 +499,75 @@ func (ts *TimeSeries) Transform(transform Transform) *TimeSeries {
 		tts.data[i] = transform.Transform(v)
 	}
 
+	mustSameShape(ts, tts)
 	return tts
 }
 
+// Apply returns a copy where fn was applied to each value, NaN included, and
+// keyed as name(key).
+func (ts *TimeSeries) Apply(name string, fn func(float64) float64) *TimeSeries {
+	ats := ts.Copy()
+	ats.key = name + "(" + ts.key + ")"
+
+	for i, v := range ats.data {
+		ats.data[i] = fn(v)
+	}
+
+	mustSameShape(ts, ats)
+	return ats
+}
+
+// FormatOptions controls how Format prints a series.
+type FormatOptions struct {
+	// Precision is the number of decimals, -1 for the fewest needed.
+	Precision int
+	// MaxPoints caps the number of values printed when positive, the rest
+	// being elided.
+	MaxPoints int
+	// Header prints the key, time range, step and length before the values.
+	Header bool
+}
+
+// String prints the header and up to 20 values with two decimals.
 func (ts TimeSeries) String() string {
+	return ts.Format(FormatOptions{Precision: 2, MaxPoints: 20, Header: true})
+}
+
+// Format prints the values of the series as comma separated numbers.
+func (ts TimeSeries) Format(opts FormatOptions) string {
 	s := bytes.NewBufferString("")
-	s.WriteString(ts.key)
+	if opts.Header {
+		s.WriteString(ts.key)
 
-	s.WriteString(" Start: ")
-	s.WriteString(ts.start.String())
+		s.WriteString(" Start: ")
+		s.WriteString(ts.start.String())
 
-	s.WriteString(" End: ")
-	s.WriteString(ts.End().String())
+		s.WriteString(" End: ")
+		s.WriteString(ts.End().String())
 
-	s.WriteString(" Step: ")
-	s.WriteString(ts.step.String())
+		s.WriteString(" Step: ")
+		s.WriteString(ts.step.String())
 
-	s.WriteString(" Length: ")
-	s.WriteString(strconv.Itoa(len(ts.data)))
+		s.WriteString(" Length: ")
+		s.WriteString(strconv.Itoa(len(ts.data)))
 
-	s.WriteString(" ")
+		if len(ts.data) > 0 {
+			s.WriteString(" ")
+		}
+	}
 
-	for _, v := range ts.data {
-		s.WriteString(strconv.FormatFloat(v, 'f', 2, 64))
-		s.WriteByte(',')
+	data := ts.data
+	if opts.MaxPoints > 0 && len(data) > opts.MaxPoints {
+		data = data[:opts.MaxPoints]
+	}
+	for i, v := range data {
+		if i > 0 {
+			s.WriteByte(',')
+		}
+		s.WriteString(strconv.FormatFloat(v, 'f', opts.Precision, 64))
 	}
-	if s.Len() > 0 {
-		s.Truncate(s.Len() - 1)
+	if len(data) < len(ts.data) {
+		s.WriteString(",...")
 	}
 	return s.String()
 }
@@ -224,6 +577,18 @@ type Transform interface {
 	Transform(float64) float64
 }
 
+// ForEach calls fn on each point without copying the data, stopping as soon as
+// fn returns false. Use Data instead to get a copy that is safe to keep.
+func (ts *TimeSeries) ForEach(fn func(i int, t time.Time, v float64) bool) {
+	t := ts.start
+	for i, v := range ts.data {
+		if !fn(i, t, v) {
+			return
+		}
+		t = t.Add(ts.step)
+	}
+}
+
 func (ts *TimeSeries) Iterator() *Iterator {
 	return &Iterator{
 		cursor: ts.start,
@@ -248,6 +613,27 @@ func (it *Iterator) Last() (val float64, ok bool) {
 	return
 }
 
+// Peek returns the value at the cursor without advancing it.
+func (it *Iterator) Peek() (val float64, ok bool) {
+	return it.series.GetAt(it.cursor)
+}
+
+// Reset moves the cursor back to the start of the series.
+func (it *Iterator) Reset() {
+	it.cursor = it.series.start
+}
+
+// Seek moves the cursor to the grid time of t, it returns false and leaves the
+// cursor untouched if t is not within the series.
+func (it *Iterator) Seek(t time.Time) bool {
+	index := it.series.index(t)
+	if index == -1 {
+		return false
+	}
+	it.cursor = it.series.start.Add(time.Duration(index) * it.series.step)
+	return true
+}
+
 type IteratorTimeValue struct {
 	Iterator
 }
@@ -272,3 +658,26 @@ func (it *IteratorTimeValue) Last() (t time.Time, val float64, ok bool) {
 	val, ok = it.series.GetAt(it.cursor)
 	return
 }
+
+// ValidIterator is like IteratorTimeValue but skips over the NaN values.
+type ValidIterator struct {
+	Iterator
+}
+
+func (ts *TimeSeries) ValidIterator() *ValidIterator {
+	return &ValidIterator{Iterator{
+		cursor: ts.start,
+		series: ts,
+	}}
+}
+
+func (it *ValidIterator) Next() (t time.Time, val float64, ok bool) {
+	for {
+		t = it.cursor
+		val, ok = it.series.GetAt(it.cursor)
+		it.cursor = it.cursor.Add(it.series.step)
+		if !ok || !math.IsNaN(val) {
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import "fmt"
+
+// Add returns the element-wise sum of both series over their overlapping range.
+func (ts *TimeSeries) Add(other *TimeSeries) (*TimeSeries, error) {
+	return ts.arithmetic(other, "Add", func(a, b float64) float64 { return a + b })
+}
+
+// Sub returns the element-wise difference of both series over their
+// overlapping range.
+func (ts *TimeSeries) Sub(other *TimeSeries) (*TimeSeries, error) {
+	return ts.arithmetic(other, "Sub", func(a, b float64) float64 { return a - b })
+}
+
+// Mul returns the element-wise product of both series over their overlapping
+// range.
+func (ts *TimeSeries) Mul(other *TimeSeries) (*TimeSeries, error) {
+	return ts.arithmetic(other, "Mul", func(a, b float64) float64 { return a * b })
+}
+
+// Div returns the element-wise quotient of both series over their overlapping
+// range. Division by zero follows IEEE semantics and yields +Inf, -Inf or NaN.
+func (ts *TimeSeries) Div(other *TimeSeries) (*TimeSeries, error) {
+	return ts.arithmetic(other, "Div", func(a, b float64) float64 { return a / b })
+}
+
+// arithmetic applies op to each pair of values found at the same time in both
+// series. NaN values propagate through op following IEEE semantics.
+func (ts *TimeSeries) arithmetic(other *TimeSeries, name string, op func(float64, float64) float64) (*TimeSeries, error) {
+	a, b, err := Align(ts, other)
+	if err != nil {
+		return nil, err
+	}
+
+	mustSameShape(a, b)
+	a.key = fmt.Sprintf("%s(%s,%s)", name, ts.key, other.key)
+	for i := range a.data {
+		a.data[i] = op(a.data[i], b.data[i])
+	}
+	return a, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesArithmetic(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+	Inf := math.Inf(1)
+
+	ts0, err := NewTimeSeriesOfData("a", start, step, []float64{1, 2, NaN, 4, 5})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("b", start.Add(step), step, []float64{2, 0, 2, 0, 8, 8})
+	checkErr(t, err)
+
+	add, err := ts0.Add(ts1)
+	checkErr(t, err)
+	sub, err := ts0.Sub(ts1)
+	checkErr(t, err)
+	mul, err := ts0.Mul(ts1)
+	checkErr(t, err)
+	div, err := ts0.Div(ts1)
+	checkErr(t, err)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: add,
+			Exp: &TimeSeries{
+				key:   "Add(a,b)",
+				start: start.Add(step),
+				step:  step,
+				data:  []float64{4, NaN, 6, 5},
+			},
+		},
+		{
+			Got: sub,
+			Exp: &TimeSeries{
+				key:   "Sub(a,b)",
+				start: start.Add(step),
+				step:  step,
+				data:  []float64{0, NaN, 2, 5},
+			},
+		},
+		{
+			Got: mul,
+			Exp: &TimeSeries{
+				key:   "Mul(a,b)",
+				start: start.Add(step),
+				step:  step,
+				data:  []float64{4, NaN, 8, 0},
+			},
+		},
+		{
+			Got: div,
+			Exp: &TimeSeries{
+				key:   "Div(a,b)",
+				start: start.Add(step),
+				step:  step,
+				data:  []float64{1, NaN, 2, Inf},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+
+	ts2, err := NewTimeSeriesOfData("c", start, time.Second, []float64{1, 2})
+	checkErr(t, err)
+	if _, err := ts0.Add(ts2); err == nil {
+		t.Errorf("FAIL(step): expected error for different steps")
+	}
+
+	ts3, err := NewTimeSeriesOfData("d", start.Add(time.Hour), step, []float64{1, 2})
+	checkErr(t, err)
+	if _, err := ts0.Add(ts3); err == nil {
+		t.Errorf("FAIL(overlap): expected error for non overlapping series")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// binaryHeader is the fixed width part of the binary format following the key.
+type binaryHeader struct {
+	Start  int64
+	Step   int64
+	Filler float64
+	Length uint64
+}
+
+const (
+	maxBinaryKey   = 1 << 16
+	binaryReadSize = 4096
+)
+
+// WriteBinary writes the series in a compact little endian format: the length
+// of the key as an uint32 followed by the key, the start in Unix nanoseconds
+// and the step in nanoseconds as int64, the filler as a float64, the number of
+// points as an uint64 and the values as float64. A final byte is 1 if the
+// weights follow as float64, one per point, and 0 otherwise.
+func (ts *TimeSeries) WriteBinary(w io.Writer) error {
+	if len(ts.key) > maxBinaryKey {
+		return fmt.Errorf("key of %d bytes is longer than %d", len(ts.key), maxBinaryKey)
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(ts.key))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, ts.key); err != nil {
+		return err
+	}
+	header := binaryHeader{
+		Start:  ts.start.UnixNano(),
+		Step:   int64(ts.step),
+		Filler: ts.filler,
+		Length: uint64(len(ts.data)),
+	}
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, ts.data); err != nil {
+		return err
+	}
+	if ts.weights == nil {
+		return binary.Write(w, binary.LittleEndian, uint8(0))
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint8(1)); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, ts.weights)
+}
+
+// ReadBinary reads a series written by WriteBinary, its start being in UTC.
+func ReadBinary(r io.Reader) (*TimeSeries, error) {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return nil, err
+	}
+	if size > maxBinaryKey {
+		return nil, fmt.Errorf("key of %d bytes is longer than %d", size, maxBinaryKey)
+	}
+	key := make([]byte, size)
+	if _, err := io.ReadFull(r, key); err != nil {
+		return nil, err
+	}
+
+	var header binaryHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+
+	data, err := readBinaryFloats(r, header.Length)
+	if err != nil {
+		return nil, err
+	}
+
+	var weighted uint8
+	if err := binary.Read(r, binary.LittleEndian, &weighted); err != nil {
+		return nil, err
+	}
+	var weights []float64
+	switch weighted {
+	case 0:
+	case 1:
+		if weights, err = readBinaryFloats(r, header.Length); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid weights flag %d", weighted)
+	}
+
+	ts := &TimeSeries{
+		key:     string(key),
+		start:   time.Unix(0, header.Start).UTC(),
+		step:    time.Duration(header.Step),
+		data:    data,
+		filler:  header.Filler,
+		weights: weights,
+	}
+	if err := ts.Verify(); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// readBinaryFloats reads length float64 by chunks so that a corrupted length
+// fails on a short read instead of allocating all of it upfront.
+func readBinaryFloats(r io.Reader, length uint64) ([]float64, error) {
+	vals := []float64{}
+	chunk := make([]float64, binaryReadSize)
+	for remaining := length; remaining > 0; {
+		n := uint64(len(chunk))
+		if remaining < n {
+			n = remaining
+		}
+		if err := binary.Read(r, binary.LittleEndian, chunk[:n]); err != nil {
+			return nil, err
+		}
+		vals = append(vals, chunk[:n]...)
+		remaining -= n
+	}
+	return vals, nil
+}
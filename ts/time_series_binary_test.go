@@ -0,0 +1,47 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesBinary(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 2.5, NaN})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 5000, 3)
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("", start, step, []float64{})
+	checkErr(t, err)
+
+	for _, exp := range []*TimeSeries{ts0, ts1, ts2} {
+		var buf bytes.Buffer
+		checkErr(t, exp.WriteBinary(&buf))
+		if size := 4 + len(exp.key) + 32 + 8*len(exp.data) + 1; buf.Len() != size {
+			t.Errorf("FAIL(size): got: '%d', expected '%d'", buf.Len(), size)
+		}
+
+		got, err := ReadBinary(&buf)
+		checkErr(t, err)
+		fmt.Printf("%s\n%s\n\n", got, exp)
+		checkTimeSeries(t, got, exp)
+		checkFloat(t, "filler", got.filler, exp.filler)
+	}
+
+	var buf bytes.Buffer
+	checkErr(t, ts0.WriteBinary(&buf))
+	if _, err := ReadBinary(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Errorf("FAIL(truncated): expected error for truncated data")
+	}
+	if _, err := ReadBinary(bytes.NewReader(buf.Bytes()[:6])); err == nil {
+		t.Errorf("FAIL(truncated): expected error for a truncated key")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import "time"
+
+// CachedSeries memoizes the downsamples of a TimeSeries, such as the ones
+// computed over and over for the same chart width. The cache is dropped
+// whenever the series is mutated in place, for instance with SetAt or
+// ExtendWith, so the series can keep being written while wrapped.
+//
+// A CachedSeries isn't safe for concurrent use.
+type CachedSeries struct {
+	series  *TimeSeries
+	version uint64
+
+	downsamples map[downsampleKey]*TimeSeries
+	lttbs       map[int]*TimeSeries
+}
+
+type downsampleKey struct {
+	step time.Duration
+	agg  string
+}
+
+// NewCachedSeries wraps ts, which is not copied.
+func NewCachedSeries(ts *TimeSeries) *CachedSeries {
+	cs := &CachedSeries{series: ts}
+	cs.reset()
+	return cs
+}
+
+// Series returns the wrapped series.
+func (cs *CachedSeries) Series() *TimeSeries {
+	return cs.series
+}
+
+// Downsample is like TimeSeries.Downsample, aggregators being told apart by
+// their Name. Errors aren't cached.
+func (cs *CachedSeries) Downsample(step time.Duration, agg Aggregator) (*TimeSeries, error) {
+	cs.invalidate()
+	key := downsampleKey{step, agg.Name()}
+	if dts, ok := cs.downsamples[key]; ok {
+		return dts.Copy(), nil
+	}
+
+	dts, err := cs.series.Downsample(step, agg)
+	if err != nil {
+		return nil, err
+	}
+	cs.downsamples[key] = dts
+	return dts.Copy(), nil
+}
+
+// DownsampleLTTB is like TimeSeries.DownsampleLTTB.
+func (cs *CachedSeries) DownsampleLTTB(threshold int) *TimeSeries {
+	cs.invalidate()
+	if dts, ok := cs.lttbs[threshold]; ok {
+		return dts.Copy()
+	}
+
+	dts := cs.series.DownsampleLTTB(threshold)
+	cs.lttbs[threshold] = dts
+	return dts.Copy()
+}
+
+// invalidate drops the cache if the series changed since it was filled.
+func (cs *CachedSeries) invalidate() {
+	if cs.version != cs.series.version {
+		cs.reset()
+	}
+}
+
+func (cs *CachedSeries) reset() {
+	cs.version = cs.series.version
+	cs.downsamples = make(map[downsampleKey]*TimeSeries)
+	cs.lttbs = make(map[int]*TimeSeries)
+}
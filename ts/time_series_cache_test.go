@@ -0,0 +1,58 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachedSeries(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3, 4})
+	checkErr(t, err)
+	cs := NewCachedSeries(ts0)
+
+	got, err := cs.Downsample(2*step, &SumAggregator{})
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "Downsample(2m0s,Sum)(test0)",
+		start: start,
+		step:  2 * step,
+		data:  []float64{3, 7},
+	})
+	if len(cs.downsamples) != 1 {
+		t.Errorf("FAIL(cache): got: '%d', expected '%d'", len(cs.downsamples), 1)
+	}
+
+	got.data[0] = 0
+	again, err := cs.Downsample(2*step, &SumAggregator{})
+	checkErr(t, err)
+	checkData(t, again.data, []float64{3, 7})
+
+	mean, err := cs.Downsample(2*step, &MeanAggregator{})
+	checkErr(t, err)
+	checkData(t, mean.data, []float64{1.5, 3.5})
+
+	if _, err := cs.Downsample(90*time.Second, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(error): expected an error for a misaligned step")
+	}
+
+	lttb := cs.DownsampleLTTB(3)
+	checkData(t, lttb.data, ts0.DownsampleLTTB(3).data)
+
+	ts0.SetAt(start, 10)
+	got, err = cs.Downsample(2*step, &SumAggregator{})
+	checkErr(t, err)
+	checkData(t, got.data, []float64{12, 7})
+	if len(cs.lttbs) != 0 {
+		t.Errorf("FAIL(cache): got: '%d', expected '%d'", len(cs.lttbs), 0)
+	}
+
+	ts0.ExtendWith(5, 6)
+	got, err = cs.Downsample(2*step, &SumAggregator{})
+	checkErr(t, err)
+	checkData(t, got.data, []float64{12, 7, 11})
+}
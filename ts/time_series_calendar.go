@@ -0,0 +1,84 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CalendarUnit is a period of the local calendar whose length varies, such as
+// days around DST changes or months.
+type CalendarUnit int
+
+const (
+	CalendarDay CalendarUnit = iota
+	CalendarMonth
+)
+
+func (unit CalendarUnit) String() string {
+	switch unit {
+	case CalendarDay:
+		return "Day"
+	case CalendarMonth:
+		return "Month"
+	default:
+		return fmt.Sprintf("CalendarUnit(%d)", int(unit))
+	}
+}
+
+// truncate returns the local midnight starting the period holding t.
+func (unit CalendarUnit) truncate(t time.Time, loc *time.Location) (time.Time, error) {
+	t = t.In(loc)
+	switch unit {
+	case CalendarDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc), nil
+	case CalendarMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown calendar unit %v", unit)
+	}
+}
+
+// DownsampleCalendar aggregates the non NaN values of the points falling in
+// each day or month of the calendar of loc, which can't be done with
+// Downsample since those periods don't have a fixed length. Each period
+// holding points is returned at its local midnight, as NaN if all its values
+// are NaN.
+func (ts *TimeSeries) DownsampleCalendar(unit CalendarUnit, loc *time.Location, agg Aggregator) (*SparseSeries, error) {
+	if loc == nil {
+		return nil, errors.New("location can't be nil")
+	}
+
+	ss := NewSparseSeries(fmt.Sprintf("Downsample(%v,%s)(%s)", unit, agg.Name(), ts.key))
+	var period time.Time
+	bucket := []float64{}
+	flush := func() {
+		v := math.NaN()
+		if len(bucket) > 0 {
+			v = agg.Aggregate(bucket)
+		}
+		ss.InsertAt(period, v)
+		bucket = bucket[:0]
+	}
+
+	for i, v := range ts.data {
+		p, err := unit.truncate(ts.TimeAt(i), loc)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && !p.Equal(period) {
+			flush()
+		}
+		period = p
+		if !math.IsNaN(v) {
+			bucket = append(bucket, v)
+		}
+	}
+	if len(ts.data) > 0 {
+		flush()
+	}
+	return ss, nil
+}
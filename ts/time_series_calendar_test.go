@@ -0,0 +1,54 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesDownsampleCalendar(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %s", err)
+	}
+
+	// DST starts on 2016-03-13 which only lasts 23 hours.
+	start := time.Date(2016, time.Month(3), 12, 0, 0, 0, 0, loc)
+	ts0, err := NewTimeSeriesOfLength("test0", start, time.Hour, 48, 1)
+	checkErr(t, err)
+	ts0.SetAt(start.Add(time.Hour), NaN)
+
+	got, err := ts0.DownsampleCalendar(CalendarDay, loc, &SumAggregator{})
+	checkErr(t, err)
+	checkKey(t, got.Key(), "Downsample(Day,Sum)(test0)")
+	checkData(t, got.Data(), []float64{23, 23, 1})
+	for i, exp := range []time.Time{
+		start,
+		time.Date(2016, time.Month(3), 13, 0, 0, 0, 0, loc),
+		time.Date(2016, time.Month(3), 14, 0, 0, 0, 0, loc),
+	} {
+		if i < got.Len() && !got.Times()[i].Equal(exp) {
+			t.Errorf("FAIL(day %d): got: '%s', expected '%s'", i, got.Times()[i], exp)
+		}
+	}
+
+	utc := time.Date(2016, time.Month(1), 31, 0, 0, 0, 0, time.UTC)
+	ts1, err := NewTimeSeriesOfData("test1", utc, 12*time.Hour, []float64{1, 2, NaN, 4})
+	checkErr(t, err)
+
+	got, err = ts1.DownsampleCalendar(CalendarMonth, time.UTC, &MeanAggregator{})
+	checkErr(t, err)
+	fmt.Println(got.Times(), got.Data())
+	checkData(t, got.Data(), []float64{1.5, 4})
+
+	got, err = ts1.DownsampleCalendar(CalendarMonth, time.FixedZone("UTC+13", 13*3600), &MeanAggregator{})
+	checkErr(t, err)
+	fmt.Println(got.Times(), got.Data())
+	checkData(t, got.Data(), []float64{1, 3})
+
+	if _, err := ts1.DownsampleCalendar(CalendarDay, nil, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(location): expected error for a nil location")
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Merge overlays the series over the union of their time ranges. Each point is
+// taken from the first series, in argument order, holding a non NaN value at
+// that time.
+func Merge(key string, series ...*TimeSeries) (*TimeSeries, error) {
+	if len(series) == 0 {
+		return nil, errors.New("no time series to merge")
+	}
+
+	first := series[0]
+	start, end := first.start, first.End()
+	for _, ts := range series[1:] {
+		if !first.IsEqualStep(ts) {
+			return nil, fmt.Errorf("step %v != %v", first.step, ts.step)
+		}
+		if start.After(ts.start) {
+			start = ts.start
+		}
+		if end.Before(ts.End()) {
+			end = ts.End()
+		}
+	}
+
+	result := &TimeSeries{
+		key:    key,
+		start:  start,
+		step:   first.step,
+		data:   make([]float64, end.Sub(start)/first.step),
+		filler: first.filler,
+	}
+
+	cursor := start
+	for i := range result.data {
+		result.data[i] = math.NaN()
+		for _, ts := range series {
+			if v, ok := ts.GetAt(cursor); ok && !math.IsNaN(v) {
+				result.data[i] = v
+				break
+			}
+		}
+		cursor = cursor.Add(result.step)
+	}
+	return result, nil
+}
+
+// Align returns copies of both series trimmed to their overlapping range, so
+// that they share the same start and length and can be compared index for
+// index. Both series must be on the same grid.
+func Align(a, b *TimeSeries) (*TimeSeries, *TimeSeries, error) {
+	if err := sameGrid(a, b); err != nil {
+		return nil, nil, err
+	}
+
+	start := a.start
+	if start.Before(b.start) {
+		start = b.start
+	}
+	end := a.End()
+	if end.After(b.End()) {
+		end = b.End()
+	}
+	if !start.Before(end) {
+		return nil, nil, fmt.Errorf("time series '%s' and '%s' don't overlap", a.key, b.key)
+	}
+
+	size := int(end.Sub(start) / a.step)
+	return a.onto(start, size), b.onto(start, size), nil
+}
+
+// ConformTo returns a copy of the series on the grid of ref, with the same
+// start, step and length. When ref has a coarser step each point is the mean
+// of the non NaN values within [t, t+step), otherwise it is linearly
+// interpolated between the two surrounding points, any of them being NaN or
+// missing yielding NaN. Both series must overlap.
+func (ts *TimeSeries) ConformTo(ref *TimeSeries) (*TimeSeries, error) {
+	start := ts.start
+	if start.Before(ref.start) {
+		start = ref.start
+	}
+	end := ts.End()
+	if end.After(ref.End()) {
+		end = ref.End()
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("time series '%s' and '%s' don't overlap", ts.key, ref.key)
+	}
+
+	cts := &TimeSeries{
+		key:    ts.key,
+		start:  ref.start,
+		step:   ref.step,
+		data:   make([]float64, len(ref.data)),
+		filler: ts.filler,
+	}
+	for i := range cts.data {
+		t := cts.TimeAt(i)
+		if ref.step > ts.step {
+			cts.data[i] = ts.meanWithin(t, t.Add(ref.step))
+		} else {
+			cts.data[i] = ts.interpolateAt(t)
+		}
+	}
+	mustSameShape(ref, cts)
+	return cts, nil
+}
+
+// meanWithin returns the mean of the non NaN values in [from, to), or NaN.
+func (ts *TimeSeries) meanWithin(from, to time.Time) float64 {
+	first := ceilSteps(from.Sub(ts.start), ts.step)
+	last := ceilSteps(to.Sub(ts.start), ts.step)
+	if first < 0 {
+		first = 0
+	}
+	if last > len(ts.data) {
+		last = len(ts.data)
+	}
+
+	var sum float64
+	var count int
+	for j := first; j < last; j++ {
+		if v := ts.data[j]; !math.IsNaN(v) {
+			sum += v
+			count++
+		}
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return sum / float64(count)
+}
+
+// interpolateAt returns the value at t linearly interpolated between the
+// surrounding points, or NaN if any of them is NaN or outside the series.
+func (ts *TimeSeries) interpolateAt(t time.Time) float64 {
+	distance := t.Sub(ts.start)
+	index := distance / ts.step
+	remainder := distance % ts.step
+	if remainder < 0 {
+		index--
+		remainder += ts.step
+	}
+
+	i := int(index)
+	if i < 0 || i >= len(ts.data) {
+		return math.NaN()
+	}
+	if remainder == 0 {
+		return ts.data[i]
+	}
+	if i+1 >= len(ts.data) {
+		return math.NaN()
+	}
+	from, to := ts.data[i], ts.data[i+1]
+	return from + (to-from)*float64(remainder)/float64(ts.step)
+}
+
+// ceilSteps returns the number of steps in d rounded up.
+func ceilSteps(d, step time.Duration) int {
+	steps := d / step
+	if d%step > 0 {
+		steps++
+	}
+	return int(steps)
+}
+
+// mustSameShape panics unless both series have the same start, step and
+// length, as the element-wise operations expect after Align.
+// sameGrid returns an error unless both series have the same step and times
+// that are a whole number of steps apart.
+func sameGrid(a, b *TimeSeries) error {
+	if !a.IsEqualStep(b) {
+		return fmt.Errorf("step %v != %v", a.step, b.step)
+	}
+	if b.start.Sub(a.start)%a.step != 0 {
+		return fmt.Errorf("time series '%s' and '%s' are not on the same grid", a.key, b.key)
+	}
+	return nil
+}
+
+func mustSameShape(a, b *TimeSeries) {
+	if !a.start.Equal(b.start) || a.step != b.step || len(a.data) != len(b.data) {
+		panic(fmt.Sprintf("time series '%s' (%v, %v, %d points) and '%s' (%v, %v, %d points) don't have the same shape",
+			a.key, a.start, a.step, len(a.data), b.key, b.start, b.step, len(b.data)))
+	}
+}
+
+// onto returns a copy of the series over size points starting at start.
+func (ts *TimeSeries) onto(start time.Time, size int) *TimeSeries {
+	ots := &TimeSeries{
+		key:    ts.key,
+		start:  start,
+		step:   ts.step,
+		data:   make([]float64, size),
+		filler: ts.filler,
+	}
+
+	cursor := start
+	for i := range ots.data {
+		ots.data[i], _ = ts.GetAt(cursor)
+		cursor = cursor.Add(ts.step)
+	}
+	return ots
+}
+
+// Append returns a copy of the series followed by other, which must start at
+// or after its end on the same grid. A gap between both series is filled with
+// the filler if fillGap is set and is an error otherwise.
+func (ts *TimeSeries) Append(other *TimeSeries, fillGap bool) (*TimeSeries, error) {
+	if !ts.IsEqualStep(other) {
+		return nil, fmt.Errorf("step %v != %v", ts.step, other.step)
+	}
+
+	end := ts.End()
+	if other.start.Before(end) {
+		return nil, fmt.Errorf("time series '%s' starts at %v before the end %v", other.key, other.start, end)
+	}
+	gap := other.start.Sub(end)
+	if gap%ts.step != 0 {
+		return nil, fmt.Errorf("time series '%s' is not on the same grid", other.key)
+	}
+	if gap != 0 && !fillGap {
+		return nil, fmt.Errorf("gap of %v between %v and %v", gap, end, other.start)
+	}
+
+	ats := ts.Copy()
+	ats.ExtendBy(gap)
+	ats.ExtendWith(other.data...)
+	return ats, nil
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesMerge(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 3})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start.Add(step), step, []float64{20, 30, NaN, 50})
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("test2", start.Add(7*step), step, []float64{800})
+	checkErr(t, err)
+
+	merged, err := Merge("merged", ts0, ts1, ts2)
+	checkErr(t, err)
+
+	checkTimeSeries(t, merged, &TimeSeries{
+		key:   "merged",
+		start: start,
+		step:  step,
+		data:  []float64{1, 20, 3, NaN, 50, NaN, NaN, 800},
+	})
+	fmt.Println(merged)
+
+	ts3, err := NewTimeSeriesOfData("test3", start, time.Second, []float64{1})
+	checkErr(t, err)
+	if _, err := Merge("bad", ts0, ts3); err == nil {
+		t.Errorf("FAIL(step): expected error for different steps")
+	}
+	if _, err := Merge("empty"); err == nil {
+		t.Errorf("FAIL(merge): expected error without series")
+	}
+}
+
+func TestTimeSeriesAlign(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3, 4})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start.Add(2*step), step, []float64{30, 40, 50})
+	checkErr(t, err)
+
+	a, b, err := Align(ts0, ts1)
+	checkErr(t, err)
+
+	checkTimeSeries(t, a, &TimeSeries{
+		key:   "test0",
+		start: start.Add(2 * step),
+		step:  step,
+		data:  []float64{3, 4},
+	})
+	checkTimeSeries(t, b, &TimeSeries{
+		key:   "test1",
+		start: start.Add(2 * step),
+		step:  step,
+		data:  []float64{30, 40},
+	})
+
+	ts2, err := NewTimeSeriesOfData("test2", start.Add(4*step), step, []float64{5})
+	checkErr(t, err)
+	if _, _, err := Align(ts0, ts2); err == nil {
+		t.Errorf("FAIL(overlap): expected error for non overlapping series")
+	}
+
+	ts3, err := NewTimeSeriesOfData("test3", start, time.Second, []float64{1})
+	checkErr(t, err)
+	if _, _, err := Align(ts0, ts3); err == nil {
+		t.Errorf("FAIL(step): expected error for different steps")
+	}
+
+	ts4, err := NewTimeSeriesOfData("test4", start.Add(30*time.Second), ts0.step, []float64{10, 20})
+	checkErr(t, err)
+	if _, _, err := Align(ts0, ts4); err == nil {
+		t.Errorf("FAIL(grid): expected error for series off the grid")
+	}
+	if _, err := ts0.Add(ts4); err == nil {
+		t.Errorf("FAIL(grid): expected error adding series off the grid")
+	}
+}
+
+func TestTimeSeriesAppend(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start.Add(2*step), step, []float64{3, 4})
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("test2", start.Add(4*step), step, []float64{5})
+	checkErr(t, err)
+
+	got, err := ts0.Append(ts1, false)
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "test0",
+		start: start,
+		step:  step,
+		data:  []float64{1, 2, 3, 4},
+	})
+
+	got, err = ts0.Append(ts2, true)
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "test0",
+		start: start,
+		step:  step,
+		data:  []float64{1, 2, NaN, NaN, 5},
+	})
+	checkLengthDataEqual(t, ts0.data, 2)
+
+	if _, err := ts0.Append(ts2, false); err == nil {
+		t.Errorf("FAIL(gap): expected error for a gap without filling")
+	}
+	if _, err := ts1.Append(ts0, true); err == nil {
+		t.Errorf("FAIL(order): expected error for a series starting before the end")
+	}
+
+	ts3, err := NewTimeSeriesOfData("test3", start.Add(150*time.Second), step, []float64{1})
+	checkErr(t, err)
+	if _, err := ts0.Append(ts3, true); err == nil {
+		t.Errorf("FAIL(grid): expected error for a series off the grid")
+	}
+
+	ring := NewRingTimeSeries("ring", start, step, 3)
+	ring.ExtendWith(1, 2, 3)
+	ts4, err := NewTimeSeriesOfData("test4", ring.End(), step, []float64{4, 5})
+	checkErr(t, err)
+	got, err = ring.Append(ts4, false)
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "ring",
+		start: start,
+		step:  step,
+		data:  []float64{1, 2, 3, 4, 5},
+	})
+	checkLengthDataEqual(t, ring.data, 3)
+	if copied := ring.Copy(); copied.capacity != 0 {
+		t.Errorf("FAIL(capacity): got: '%d', expected '%d'", copied.capacity, 0)
+	}
+}
+
+func TestMustSameShape(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3})
+	checkErr(t, err)
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{4, NaN, 6})
+	checkErr(t, err)
+	mustSameShape(ts0, ts1)
+
+	shapes := []*TimeSeries{
+		ts0.sub(0, 2),
+		ts0.Rebase(start.Add(step)),
+		{key: "test2", start: start, step: 2 * step, data: []float64{1, 2, 3}},
+	}
+	for _, other := range shapes {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("FAIL(shape): expected panic for '%s'", other)
+				}
+			}()
+			mustSameShape(ts0, other)
+		}()
+	}
+}
+
+func TestTimeSeriesConformTo(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0 := MustTimeSeriesOfData("test0", start, step, []float64{1, 2, NaN, 4, 5, 6})
+
+	tss := []struct {
+		Ref *TimeSeries
+		Exp []float64
+	}{
+		{
+			Ref: MustTimeSeriesOfData("coarser", start, 2*step, []float64{0, 0, 0, 0}),
+			Exp: []float64{1.5, 4, 5.5, NaN},
+		},
+		{
+			Ref: MustTimeSeriesOfData("coarser", start.Add(-90*time.Second), 2*step, []float64{0, 0}),
+			Exp: []float64{1, 2},
+		},
+		{
+			Ref: MustTimeSeriesOfData("finer", start.Add(3*step), step/2, []float64{0, 0, 0, 0, 0, 0}),
+			Exp: []float64{4, 4.5, 5, 5.5, 6, NaN},
+		},
+		{
+			Ref: MustTimeSeriesOfData("finer", start.Add(-step/2), step/2, []float64{0, 0, 0, 0, 0}),
+			Exp: []float64{NaN, 1, 1.5, 2, NaN},
+		},
+		{
+			Ref: MustTimeSeriesOfData("misaligned", start.Add(15*time.Second), step, []float64{0, 0, 0, 0}),
+			Exp: []float64{1.25, NaN, NaN, 4.25},
+		},
+	}
+
+	for _, tt := range tss {
+		got, err := ts0.ConformTo(tt.Ref)
+		checkErr(t, err)
+		exp := &TimeSeries{
+			key:   "test0",
+			start: tt.Ref.start,
+			step:  tt.Ref.step,
+			data:  tt.Exp,
+		}
+		fmt.Printf("%s\n%s\n\n", got, exp)
+		checkTimeSeries(t, got, exp)
+	}
+
+	ref := MustTimeSeriesOfData("disjoint", ts0.End(), step, []float64{0})
+	if _, err := ts0.ConformTo(ref); err == nil {
+		t.Errorf("FAIL(error): expected an error for disjoint series")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DominantPeriod returns the lag, as a duration, of the highest peak of the
+// autocorrelation of the series. Internal NaN values are interpolated first
+// and leading or trailing ones are ignored.
+func (ts *TimeSeries) DominantPeriod() (time.Duration, error) {
+	vals := ts.InterpolateLinear().valid()
+	if len(vals) < 4 {
+		return 0, errors.New("time series is too short to find a period")
+	}
+
+	var mean float64
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+
+	var variance float64
+	for _, v := range vals {
+		variance += (v - mean) * (v - mean)
+	}
+	if variance == 0 {
+		return 0, errors.New("time series is flat")
+	}
+
+	maxLag := len(vals) / 2
+	acf := make([]float64, maxLag+2)
+	for lag := range acf {
+		var sum float64
+		for i := 0; i+lag < len(vals); i++ {
+			sum += (vals[i] - mean) * (vals[i+lag] - mean)
+		}
+		acf[lag] = sum / variance
+	}
+
+	best := 0
+	for lag := 1; lag <= maxLag; lag++ {
+		if acf[lag] > acf[lag-1] && acf[lag] >= acf[lag+1] {
+			if best == 0 || acf[lag] > acf[best] {
+				best = lag
+			}
+		}
+	}
+	if best == 0 {
+		return 0, errors.New("no periodicity found")
+	}
+	return time.Duration(best) * ts.step, nil
+}
+
+// CrossCorrelation returns the Pearson correlation between the series and
+// other shifted by each lag from -maxLag to maxLag steps, the correlation at
+// lag l pairing the value at t with the value of other at t+l*step. A peak at a
+// positive lag thus means that other lags behind. Both series must be on the
+// same grid. Lags with less than two pairs of non NaN values yield NaN.
+func (ts *TimeSeries) CrossCorrelation(other *TimeSeries, maxLag int) ([]float64, error) {
+	if err := sameGrid(ts, other); err != nil {
+		return nil, err
+	}
+	if maxLag < 0 {
+		return nil, fmt.Errorf("max lag %d can't be negative", maxLag)
+	}
+
+	ccf := make([]float64, 2*maxLag+1)
+	for k := range ccf {
+		// Moving other back by the lag pairs t with t+lag once aligned.
+		lag := time.Duration(k-maxLag) * ts.step
+		shifted := &TimeSeries{
+			key:    other.key,
+			start:  other.start.Add(-lag),
+			step:   other.step,
+			data:   other.data,
+			filler: other.filler,
+		}
+
+		r := math.NaN()
+		if xs, ys, err := ts.pairs(shifted); err == nil {
+			if c, err := pearson(xs, ys); err == nil {
+				r = c
+			}
+		}
+		ccf[k] = r
+	}
+	return ccf, nil
+}
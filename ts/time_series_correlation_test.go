@@ -0,0 +1,86 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesDominantPeriod(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	data := make([]float64, 48)
+	for i := range data {
+		data[i] = math.Sin(2 * math.Pi * float64(i) / 6)
+	}
+	data[3] = NaN
+	data[20] = NaN
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, data)
+	checkErr(t, err)
+
+	period, err := ts0.DominantPeriod()
+	checkErr(t, err)
+	if period != 6*step {
+		t.Errorf("FAIL(period): got: '%s', expected '%s'", period, 6*step)
+	}
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 10, 1)
+	checkErr(t, err)
+	if _, err := ts1.DominantPeriod(); err == nil {
+		t.Errorf("FAIL(period): expected error for a flat series")
+	}
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{1, 2})
+	checkErr(t, err)
+	if _, err := ts2.DominantPeriod(); err == nil {
+		t.Errorf("FAIL(period): expected error for a short series")
+	}
+}
+
+func TestTimeSeriesCrossCorrelation(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	lead, err := NewTimeSeriesOfData("lead", start, step, []float64{1, 2, 4, 2, 1, 3, 5, 2, NaN, 1})
+	checkErr(t, err)
+
+	lag := lead.Shift(2)
+	lag.SetKey("lag")
+
+	ccf, err := lead.CrossCorrelation(lag, 3)
+	checkErr(t, err)
+	if len(ccf) != 7 {
+		t.Fatalf("FAIL(length): got: '%d', expected '%d'", len(ccf), 7)
+	}
+
+	best := 0
+	for i := range ccf {
+		if ccf[i] > ccf[best] {
+			best = i
+		}
+	}
+	if best-3 != 2 {
+		t.Errorf("FAIL(lag): got: '%d', expected '%d' in %v", best-3, 2, ccf)
+	}
+	if math.Abs(ccf[best]-1) > 1e-9 {
+		t.Errorf("FAIL(peak): got: '%f', expected '%f'", ccf[best], 1.0)
+	}
+
+	other, err := NewTimeSeriesOfData("other", start, time.Second, []float64{1, 2})
+	checkErr(t, err)
+	if _, err := lead.CrossCorrelation(other, 1); err == nil {
+		t.Errorf("FAIL(step): expected error for different steps")
+	}
+	offGrid, err := NewTimeSeriesOfData("offGrid", start.Add(30*time.Second), step, []float64{1, 2})
+	checkErr(t, err)
+	if _, err := lead.CrossCorrelation(offGrid, 1); err == nil {
+		t.Errorf("FAIL(grid): expected error for series off the grid")
+	}
+	if _, err := lead.CrossCorrelation(lag, -1); err == nil {
+		t.Errorf("FAIL(lag): expected error for a negative max lag")
+	}
+}
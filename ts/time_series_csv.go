@@ -0,0 +1,85 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// NewTimeSeriesFromCSV reads 'RFC3339,value' rows from r. The timestamps must
+// be evenly spaced by step and an empty value is read as NaN, which is also the
+// filler of the series.
+func NewTimeSeriesFromCSV(key string, r io.Reader, step time.Duration) (*TimeSeries, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+
+	var start time.Time
+	data := []float64{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := time.Parse(time.RFC3339, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		if line == 1 {
+			start = t
+		} else if exp := start.Add(time.Duration(len(data)) * step); !t.Equal(exp) {
+			return nil, fmt.Errorf("line %d: time %v is not evenly spaced, expected %v", line, t, exp)
+		}
+
+		v := math.NaN()
+		if record[1] != "" {
+			if v, err = strconv.ParseFloat(record[1], 64); err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err)
+			}
+		}
+		data = append(data, v)
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("no data in csv")
+	}
+	ts := &TimeSeries{
+		key:    key,
+		start:  start,
+		step:   step,
+		data:   data,
+		filler: math.NaN(),
+	}
+	if err := ts.Verify(); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// WriteCSV writes one 'RFC3339,value' row per point, NaN values are written as
+// an empty value.
+func (ts *TimeSeries) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	it := ts.IteratorTimeValue()
+	for t, v, ok := it.Next(); ok; t, v, ok = it.Next() {
+		value := ""
+		if !math.IsNaN(v) {
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		if err := writer.Write([]string{t.Format(time.RFC3339), value}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
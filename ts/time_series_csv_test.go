@@ -0,0 +1,67 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesCSV(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 2.5})
+	checkErr(t, err)
+
+	var buf bytes.Buffer
+	checkErr(t, ts0.WriteCSV(&buf))
+	fmt.Println(buf.String())
+
+	exp := "2016-02-01T10:00:00Z,1\n2016-02-01T10:01:00Z,\n2016-02-01T10:02:00Z,2.5\n"
+	if buf.String() != exp {
+		t.Errorf("FAIL(csv): got:\n%s\nexpected:\n%s", buf.String(), exp)
+	}
+
+	got, err := NewTimeSeriesFromCSV("test0", &buf, step)
+	checkErr(t, err)
+	checkTimeSeries(t, got, ts0)
+
+	single, err := NewTimeSeriesFromCSV("test1", strings.NewReader("2016-02-01T10:00:00Z,5\n"), step)
+	checkErr(t, err)
+	checkFloat(t, "filler", single.Filler(), NaN)
+	single.ExtendBy(step)
+	checkData(t, single.data, []float64{5, NaN})
+
+	if _, err := NewTimeSeriesFromCSV("test1", strings.NewReader("2016-02-01T10:00:00Z,5\n"), 0); err == nil {
+		t.Errorf("FAIL(step): expected error for a zero step")
+	}
+
+	tests := []struct {
+		csv  string
+		line string
+	}{
+		{
+			csv:  "2016-02-01T10:00:00Z,1\n2016-02-01T10:02:00Z,2\n",
+			line: "line 2",
+		},
+		{
+			csv:  "2016-02-01T10:00:00Z,1\nyesterday,2\n",
+			line: "line 2",
+		},
+		{
+			csv:  "2016-02-01T10:00:00Z,1\n2016-02-01T10:01:00Z,2\n2016-02-01T10:02:00Z,two\n",
+			line: "line 3",
+		},
+	}
+
+	for i, test := range tests {
+		_, err := NewTimeSeriesFromCSV("bad", strings.NewReader(test.csv), step)
+		if err == nil || !strings.Contains(err.Error(), test.line) {
+			t.Errorf("FAIL(%d): expected error on '%s', got '%v'", i, test.line, err)
+		}
+	}
+}
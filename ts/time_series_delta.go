@@ -0,0 +1,194 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Diff returns the difference between each point and its predecessor. The
+// first point has no predecessor and is NaN, as is any delta involving a NaN.
+func (ts *TimeSeries) Diff() *TimeSeries {
+	return ts.diff("Diff", false)
+}
+
+// DiffNonNegative is like Diff but clamps negative deltas, such as the ones
+// caused by counter resets, to zero.
+func (ts *TimeSeries) DiffNonNegative() *TimeSeries {
+	return ts.diff("DiffNonNegative", true)
+}
+
+func (ts *TimeSeries) diff(name string, nonNegative bool) *TimeSeries {
+	dts := ts.Copy()
+	dts.key = name + "(" + ts.key + ")"
+
+	for i := range dts.data {
+		if i == 0 {
+			dts.data[i] = math.NaN()
+			continue
+		}
+		delta := ts.data[i] - ts.data[i-1]
+		if nonNegative && delta < 0 {
+			delta = 0
+		}
+		dts.data[i] = delta
+	}
+	return dts
+}
+
+// Rate returns the per second rate of a counter, that is the delta between
+// each point and its predecessor divided by the step. A negative delta is a
+// counter reset: when resetToValue is set the counter is assumed to have
+// restarted from zero and the rate is the current value over the step,
+// otherwise the rate is NaN.
+func (ts *TimeSeries) Rate(resetToValue bool) *TimeSeries {
+	rts := ts.diff("Rate", false)
+	seconds := ts.step.Seconds()
+
+	for i, delta := range rts.data {
+		if delta < 0 {
+			if !resetToValue {
+				rts.data[i] = math.NaN()
+				continue
+			}
+			delta = ts.data[i]
+		}
+		rts.data[i] = delta / seconds
+	}
+	return rts
+}
+
+// CumSum returns the running total of the series. NaN values don't contribute
+// to the total and stay NaN in the result so that gaps remain visible, unlike
+// the CumulativeSum transform which carries the previous total forward.
+func (ts *TimeSeries) CumSum() *TimeSeries {
+	cts := ts.Copy()
+	cts.key = "CumSum(" + ts.key + ")"
+
+	var sum float64
+	for i, v := range cts.data {
+		if math.IsNaN(v) {
+			continue
+		}
+		sum += v
+		cts.data[i] = sum
+	}
+	return cts
+}
+
+// Derivative returns the per second derivative of the series using central
+// differences, falling back to one sided differences at the ends and next to
+// NaN values. NaN points stay NaN, as do isolated points.
+func (ts *TimeSeries) Derivative() *TimeSeries {
+	dts := ts.Copy()
+	dts.key = "Derivative(" + ts.key + ")"
+	seconds := ts.step.Seconds()
+
+	valid := func(i int) bool {
+		return i >= 0 && i < len(ts.data) && !math.IsNaN(ts.data[i])
+	}
+	for i := range ts.data {
+		switch {
+		case !valid(i):
+			dts.data[i] = math.NaN()
+		case valid(i-1) && valid(i+1):
+			dts.data[i] = (ts.data[i+1] - ts.data[i-1]) / (2 * seconds)
+		case valid(i + 1):
+			dts.data[i] = (ts.data[i+1] - ts.data[i]) / seconds
+		case valid(i - 1):
+			dts.data[i] = (ts.data[i] - ts.data[i-1]) / seconds
+		default:
+			dts.data[i] = math.NaN()
+		}
+	}
+	return dts
+}
+
+// Integral returns the running integral of the series over time in seconds
+// using the trapezoidal rule, starting from zero. As with CumSum, NaN points
+// stay NaN and the intervals around them don't add to the total.
+func (ts *TimeSeries) Integral() *TimeSeries {
+	its := ts.Copy()
+	its.key = "Integral(" + ts.key + ")"
+	seconds := ts.step.Seconds()
+
+	var sum float64
+	for i, v := range ts.data {
+		if math.IsNaN(v) {
+			continue
+		}
+		if i > 0 && !math.IsNaN(ts.data[i-1]) {
+			sum += (ts.data[i-1] + v) / 2 * seconds
+		}
+		its.data[i] = sum
+	}
+	return its
+}
+
+// AreaUnderCurve returns the integral of the whole series in value-seconds
+// using the trapezoidal rule, the final total of Integral. The intervals
+// around NaN values are skipped rather than bridged.
+func (ts *TimeSeries) AreaUnderCurve() float64 {
+	var area float64
+	for i := 1; i < len(ts.data); i++ {
+		if math.IsNaN(ts.data[i-1]) || math.IsNaN(ts.data[i]) {
+			continue
+		}
+		area += (ts.data[i-1] + ts.data[i]) / 2
+	}
+	return area * ts.step.Seconds()
+}
+
+// Crossings returns the times of the points at which the series crosses the
+// threshold from its predecessor: upwards when direction is positive,
+// downwards when negative, either way when zero. A value equal to the
+// threshold is above it. Pairs involving a NaN aren't crossings.
+func (ts *TimeSeries) Crossings(threshold float64, direction int) []time.Time {
+	crossings := []time.Time{}
+	for i := 1; i < len(ts.data); i++ {
+		prev, cur := ts.data[i-1], ts.data[i]
+		if math.IsNaN(prev) || math.IsNaN(cur) {
+			continue
+		}
+		up := prev < threshold && cur >= threshold
+		down := prev >= threshold && cur < threshold
+		if up && direction >= 0 || down && direction <= 0 {
+			crossings = append(crossings, ts.TimeAt(i))
+		}
+	}
+	return crossings
+}
+
+// FlatSegments returns the runs of at least minLen consecutive points holding
+// exactly the same non NaN value, such as the readings of a stuck sensor. The
+// end of a run is the time past its last point. It panics if minLen isn't
+// positive.
+func (ts *TimeSeries) FlatSegments(minLen int) []struct {
+	Start, End time.Time
+	Value      float64
+} {
+	if minLen < 1 {
+		panic(fmt.Sprintf("segment length %d must be positive", minLen))
+	}
+	segments := []struct {
+		Start, End time.Time
+		Value      float64
+	}{}
+
+	for from := 0; from < len(ts.data); {
+		to := from + 1
+		for to < len(ts.data) && ts.data[to] == ts.data[from] {
+			to++
+		}
+		if to-from >= minLen && !math.IsNaN(ts.data[from]) {
+			segments = append(segments, struct {
+				Start, End time.Time
+				Value      float64
+			}{ts.TimeAt(from), ts.TimeAt(to), ts.data[from]})
+		}
+		from = to
+	}
+	return segments
+}
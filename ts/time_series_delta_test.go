@@ -0,0 +1,177 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesDelta(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 3, 6, NaN, 10, 2, 5})
+	checkErr(t, err)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ts0.Rate(true),
+			Exp: &TimeSeries{
+				key:   "Rate(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 2.0 / 60, 3.0 / 60, NaN, NaN, 2.0 / 60, 3.0 / 60},
+			},
+		},
+		{
+			Got: ts0.Rate(false),
+			Exp: &TimeSeries{
+				key:   "Rate(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 2.0 / 60, 3.0 / 60, NaN, NaN, NaN, 3.0 / 60},
+			},
+		},
+		{
+			Got: ts0.Diff(),
+			Exp: &TimeSeries{
+				key:   "Diff(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 2, 3, NaN, NaN, -8, 3},
+			},
+		},
+		{
+			Got: ts0.DiffNonNegative(),
+			Exp: &TimeSeries{
+				key:   "DiffNonNegative(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 2, 3, NaN, NaN, 0, 3},
+			},
+		},
+		{
+			Got: ts0.CumSum(),
+			Exp: &TimeSeries{
+				key:   "CumSum(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{1, 4, 10, NaN, 20, 22, 27},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
+
+func TestTimeSeriesCrossings(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 5, 10, 2, NaN, 8, 1, 5})
+	checkErr(t, err)
+
+	tests := []struct {
+		Direction int
+		Exp       []int
+	}{
+		{1, []int{1, 7}},
+		{-1, []int{3, 6}},
+		{0, []int{1, 3, 6, 7}},
+	}
+
+	for _, test := range tests {
+		got := ts0.Crossings(5, test.Direction)
+		if len(got) != len(test.Exp) {
+			t.Errorf("FAIL(%d): got: '%v', expected indexes '%v'", test.Direction, got, test.Exp)
+			continue
+		}
+		for i, index := range test.Exp {
+			checkTime(t, fmt.Sprintf("crossing %d", i), got[i], start.Add(time.Duration(index)*step))
+		}
+	}
+}
+
+func TestTimeSeriesDerivativeIntegral(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := 2 * time.Second
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 4, 16, NaN, 2, 6, NaN, 1})
+	checkErr(t, err)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ts0.Derivative(),
+			Exp: &TimeSeries{
+				key:   "Derivative(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{2, 4, 6, NaN, 2, 2, NaN, NaN},
+			},
+		},
+		{
+			Got: ts0.Integral(),
+			Exp: &TimeSeries{
+				key:   "Integral(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{0, 4, 24, NaN, 24, 32, NaN, 32},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
+
+func TestTimeSeriesAreaUnderCurve(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := 2 * time.Second
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 4, 16, NaN, 2, 6, NaN, 1})
+	checkErr(t, err)
+	checkFloat(t, "area", ts0.AreaUnderCurve(), 32)
+
+	_, exp, _ := ts0.Integral().LastValid()
+	checkFloat(t, "integral", ts0.AreaUnderCurve(), exp)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{5})
+	checkErr(t, err)
+	checkFloat(t, "single", ts1.AreaUnderCurve(), 0)
+}
+
+func TestTimeSeriesFlatSegments(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 2, 2, NaN, NaN, NaN, 3, 3, 4, 4, 4})
+	checkErr(t, err)
+
+	segments := ts0.FlatSegments(3)
+	if len(segments) != 2 {
+		t.Fatalf("FAIL(segments): got: '%d', expected '%d'", len(segments), 2)
+	}
+	checkTime(t, "start", segments[0].Start, start.Add(step))
+	checkTime(t, "end", segments[0].End, start.Add(4*step))
+	checkFloat(t, "value", segments[0].Value, 2)
+	checkTime(t, "start", segments[1].Start, start.Add(9*step))
+	checkTime(t, "end", segments[1].End, ts0.End())
+	checkFloat(t, "value", segments[1].Value, 4)
+
+	if n := len(ts0.FlatSegments(1)); n != 4 {
+		t.Errorf("FAIL(segments): got: '%d', expected '%d'", n, 4)
+	}
+}
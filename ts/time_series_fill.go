@@ -0,0 +1,63 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import "math"
+
+// InterpolateLinear returns a copy where each run of NaN values surrounded by
+// valid values is replaced by a linear interpolation between them. Leading and
+// trailing NaN values are left untouched.
+func (ts *TimeSeries) InterpolateLinear() *TimeSeries {
+	its := ts.Copy()
+	its.key = "InterpolateLinear(" + ts.key + ")"
+
+	last := -1
+	for i, v := range its.data {
+		if math.IsNaN(v) {
+			continue
+		}
+		if last != -1 && i-last > 1 {
+			from := its.data[last]
+			slope := (v - from) / float64(i-last)
+			for j := last + 1; j < i; j++ {
+				its.data[j] = from + slope*float64(j-last)
+			}
+		}
+		last = i
+	}
+	return its
+}
+
+// FillForward returns a copy where each NaN value is replaced by the closest
+// previous non NaN value, leading NaN values are left untouched.
+func (ts *TimeSeries) FillForward() *TimeSeries {
+	fts := ts.Copy()
+	fts.key = "FillForward(" + ts.key + ")"
+
+	last := math.NaN()
+	for i, v := range fts.data {
+		if math.IsNaN(v) {
+			fts.data[i] = last
+			continue
+		}
+		last = v
+	}
+	return fts
+}
+
+// FillBackward returns a copy where each NaN value is replaced by the closest
+// next non NaN value, trailing NaN values are left untouched.
+func (ts *TimeSeries) FillBackward() *TimeSeries {
+	fts := ts.Copy()
+	fts.key = "FillBackward(" + ts.key + ")"
+
+	next := math.NaN()
+	for i := len(fts.data) - 1; i >= 0; i-- {
+		if math.IsNaN(fts.data[i]) {
+			fts.data[i] = next
+			continue
+		}
+		next = fts.data[i]
+	}
+	return fts
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesFill(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{NaN, 1, NaN, NaN, 4, 2, NaN, 6, NaN})
+	checkErr(t, err)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ts0.InterpolateLinear(),
+			Exp: &TimeSeries{
+				key:   "InterpolateLinear(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 1, 2, 3, 4, 2, 4, 6, NaN},
+			},
+		},
+		{
+			Got: ts0.FillForward(),
+			Exp: &TimeSeries{
+				key:   "FillForward(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 1, 1, 1, 4, 2, 2, 6, 6},
+			},
+		},
+		{
+			Got: ts0.FillBackward(),
+			Exp: &TimeSeries{
+				key:   "FillBackward(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{1, 1, 4, 4, 4, 2, 6, 6, NaN},
+			},
+		},
+		{
+			Got: ts0,
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 1, NaN, NaN, 4, 2, NaN, 6, NaN},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"encoding/gob"
+	"time"
+)
+
+// timeSeriesGob mirrors the unexported fields of a TimeSeries so that gob can
+// encode them. Floats are encoded by their bits which keeps NaN values intact.
+// Weights is nil unless set.
+type timeSeriesGob struct {
+	Key     string
+	Start   time.Time
+	Step    time.Duration
+	Data    []float64
+	Filler  float64
+	Weights []float64
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (ts *TimeSeries) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&timeSeriesGob{
+		Key:     ts.key,
+		Start:   ts.start,
+		Step:    ts.step,
+		Data:    ts.data,
+		Filler:  ts.filler,
+		Weights: ts.weights,
+	})
+	return buf.Bytes(), err
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (ts *TimeSeries) GobDecode(body []byte) error {
+	var raw timeSeriesGob
+	if err := gob.NewDecoder(bytes.NewReader(body)).Decode(&raw); err != nil {
+		return err
+	}
+
+	ts.key = raw.Key
+	ts.start = raw.Start
+	ts.step = raw.Step
+	ts.data = raw.Data
+	if ts.data == nil {
+		ts.data = []float64{}
+	}
+	ts.filler = raw.Filler
+	ts.weights = raw.Weights
+	ts.version++
+	return ts.Verify()
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesGob(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 2.5, NaN})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 2, 0)
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{})
+	checkErr(t, err)
+
+	for _, exp := range []*TimeSeries{ts0, ts1, ts2} {
+		var buf bytes.Buffer
+		checkErr(t, gob.NewEncoder(&buf).Encode(exp))
+
+		got := &TimeSeries{}
+		checkErr(t, gob.NewDecoder(&buf).Decode(got))
+
+		fmt.Printf("%s\n%s\n\n", got, exp)
+		checkTimeSeries(t, got, exp)
+		checkFloat(t, "filler", got.filler, exp.filler)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+)
+
+var namedColors = map[string]color.RGBA{
+	"black": {0, 0, 0, 255},
+	"white": {255, 255, 255, 255},
+	"red":   {255, 0, 0, 255},
+	"green": {0, 128, 0, 255},
+	"blue":  {0, 0, 255, 255},
+	"gray":  {128, 128, 128, 255},
+}
+
+// parseColor understands the named colors above and the #rgb and #rrggbb hex
+// notations.
+func parseColor(s string) (color.RGBA, error) {
+	if c, ok := namedColors[s]; ok {
+		return c, nil
+	}
+	if len(s) == 4 && s[0] == '#' {
+		s = string([]byte{'#', s[1], s[1], s[2], s[2], s[3], s[3]})
+	}
+	if len(s) != 7 || s[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("unknown color '%s'", s)
+	}
+	rgb, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("unknown color '%s'", s)
+	}
+	return color.RGBA{uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), 255}, nil
+}
+
+// RenderImage rasterizes the same line chart as RenderSVG on a white
+// background, ready to be encoded with png.Encode.
+func (ts *TimeSeries) RenderImage(opts ChartOptions) (image.Image, error) {
+	opts = opts.withDefaults()
+	stroke, err := parseColor(opts.Stroke)
+	if err != nil {
+		return nil, err
+	}
+	c := newChart(opts, ts)
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(namedColors["white"]), image.Point{}, draw.Src)
+
+	if opts.Axes {
+		black := namedColors["black"]
+		drawLine(img, point{c.left, c.top}, point{c.left, c.bottom}, black)
+		drawLine(img, point{c.left, c.bottom}, point{c.right, c.bottom}, black)
+	}
+	for _, segment := range c.segments(ts) {
+		if len(segment) == 1 {
+			drawLine(img, segment[0], segment[0], stroke)
+		}
+		for i := 1; i < len(segment); i++ {
+			drawLine(img, segment[i-1], segment[i], stroke)
+		}
+	}
+	return img, nil
+}
+
+// drawLine sets the pixels from a to b, points on the right or bottom edge of
+// the viewport being moved onto the last row or column.
+func drawLine(img *image.RGBA, a, b point, c color.RGBA) {
+	max := img.Bounds().Max
+	pixel := func(v float64, limit int) int {
+		p := int(math.Floor(v))
+		if p >= limit {
+			p = limit - 1
+		}
+		return p
+	}
+
+	steps := math.Ceil(math.Max(math.Abs(b.x-a.x), math.Abs(b.y-a.y)))
+	if steps == 0 {
+		img.SetRGBA(pixel(a.x, max.X), pixel(a.y, max.Y), c)
+		return
+	}
+	for i := 0.0; i <= steps; i++ {
+		x := a.x + (b.x-a.x)*i/steps
+		y := a.y + (b.y-a.y)*i/steps
+		img.SetRGBA(pixel(x, max.X), pixel(y, max.Y), c)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesRenderImage(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 0, NaN, 10, 10})
+	checkErr(t, err)
+
+	img, err := ts0.RenderImage(ChartOptions{Width: 9, Height: 5, Stroke: "#f00"})
+	checkErr(t, err)
+	if got := img.Bounds(); got != image.Rect(0, 0, 9, 5) {
+		t.Errorf("FAIL(bounds): got: '%v', expected '%v'", got, image.Rect(0, 0, 9, 5))
+	}
+
+	red := color.RGBA{255, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+	rgba := img.(*image.RGBA)
+	for _, test := range []struct {
+		X, Y int
+		Exp  color.RGBA
+	}{
+		{0, 4, red},
+		{2, 4, red},
+		{3, 4, white},
+		{4, 2, white},
+		{6, 0, red},
+		{8, 0, red},
+	} {
+		if got := rgba.RGBAAt(test.X, test.Y); got != test.Exp {
+			t.Errorf("FAIL(%d,%d): got: '%v', expected '%v'", test.X, test.Y, got, test.Exp)
+		}
+	}
+
+	var buf bytes.Buffer
+	checkErr(t, png.Encode(&buf, img))
+
+	if _, err := ts0.RenderImage(ChartOptions{Stroke: "nope"}); err == nil {
+		t.Errorf("FAIL(stroke): expected error for an unknown color")
+	}
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{0, 0, math.Inf(-1), 10, 10})
+	checkErr(t, err)
+
+	img, err = ts1.RenderImage(ChartOptions{Width: 9, Height: 5, Stroke: "#f00"})
+	checkErr(t, err)
+	inf := img.(*image.RGBA)
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 9; x++ {
+			if got, exp := inf.RGBAAt(x, y), rgba.RGBAAt(x, y); got != exp {
+				t.Errorf("FAIL(%d,%d): got: '%v', expected '%v'", x, y, got, exp)
+			}
+		}
+	}
+}
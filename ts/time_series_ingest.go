@@ -0,0 +1,44 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxIngestGap is the number of points by which Ingest extends a series at
+// most for a single sample, unless it is a ring series which never grows past
+// its capacity.
+const MaxIngestGap = 1 << 20
+
+// Ingest writes the samples received on in with SetAt, extending the series
+// with ExtendTo first when a sample is past the end. Samples before the start,
+// including the ones dropped by a ring series, are ignored. It returns nil
+// once in is closed, the context error if ctx is done first, or an error for
+// a sample more than MaxIngestGap points past the end, such as one with a bad
+// timestamp.
+//
+// The series is written without locking and shouldn't be read until Ingest
+// returns.
+func (ts *TimeSeries) Ingest(ctx context.Context, in <-chan struct {
+	T time.Time
+	V float64
+}) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sample, ok := <-in:
+			if !ok {
+				return nil
+			}
+			if gap := sample.T.Sub(ts.End()) / ts.step; ts.capacity <= 0 && gap >= MaxIngestGap {
+				return fmt.Errorf("sample at %v is %d points past the end %v", sample.T, gap+1, ts.End())
+			}
+			ts.ExtendTo(sample.T)
+			ts.SetAt(sample.T, sample.V)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesIngest(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1})
+	checkErr(t, err)
+	ts0.SetFiller(NaN, false)
+
+	in := make(chan struct {
+		T time.Time
+		V float64
+	}, 4)
+	in <- struct {
+		T time.Time
+		V float64
+	}{start.Add(2 * step), 3}
+	in <- struct {
+		T time.Time
+		V float64
+	}{start.Add(-step), 0}
+	in <- struct {
+		T time.Time
+		V float64
+	}{start.Add(90 * time.Second), 2}
+	close(in)
+
+	checkErr(t, ts0.Ingest(context.Background(), in))
+	checkTimeSeries(t, ts0, &TimeSeries{
+		key:   "test0",
+		start: start,
+		step:  step,
+		data:  []float64{1, 2, 3},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ts0.Ingest(ctx, make(chan struct {
+		T time.Time
+		V float64
+	})); err != context.Canceled {
+		t.Errorf("FAIL(error): got: '%v', expected '%v'", err, context.Canceled)
+	}
+}
+
+func TestTimeSeriesIngestFarFuture(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Second
+	far := start.Add(100 * 365 * 24 * time.Hour)
+
+	samples := func() <-chan struct {
+		T time.Time
+		V float64
+	} {
+		in := make(chan struct {
+			T time.Time
+			V float64
+		}, 2)
+		in <- struct {
+			T time.Time
+			V float64
+		}{start, 1}
+		in <- struct {
+			T time.Time
+			V float64
+		}{far, 2}
+		close(in)
+		return in
+	}
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{})
+	checkErr(t, err)
+	if err := ts0.Ingest(context.Background(), samples()); err == nil {
+		t.Errorf("FAIL(error): expected an error for a far future sample")
+	}
+	checkLengthDataEqual(t, ts0.data, 1)
+
+	ts1 := NewRingTimeSeries("test1", start, step, 3)
+	checkErr(t, ts1.Ingest(context.Background(), samples()))
+	checkTimeSeries(t, ts1, &TimeSeries{
+		key:   "test1",
+		start: far.Add(-2 * step),
+		step:  step,
+		data:  []float64{NaN, NaN, 2},
+	})
+	if cap(ts1.data) > 4 {
+		t.Errorf("FAIL(capacity): got: '%d', expected at most '%d'", cap(ts1.data), 4)
+	}
+}
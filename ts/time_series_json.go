@@ -0,0 +1,157 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// timeSeriesJSON is the wire representation of a TimeSeries, NaN values are
+// encoded as null since encoding/json rejects them. The weights are omitted
+// unless set.
+type timeSeriesJSON struct {
+	Key     string     `json:"key"`
+	Start   string     `json:"start"`
+	Step    string     `json:"step"`
+	Filler  *float64   `json:"filler"`
+	Data    []*float64 `json:"data"`
+	Weights []*float64 `json:"weights,omitempty"`
+}
+
+func nullable(v float64) *float64 {
+	if math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
+
+func unnullable(v *float64) float64 {
+	if v == nil {
+		return math.NaN()
+	}
+	return *v
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (ts *TimeSeries) MarshalJSON() ([]byte, error) {
+	raw := timeSeriesJSON{
+		Key:    ts.key,
+		Start:  ts.start.Format(time.RFC3339Nano),
+		Step:   ts.step.String(),
+		Filler: nullable(ts.filler),
+		Data:   make([]*float64, len(ts.data)),
+	}
+	for i, v := range ts.data {
+		raw.Data[i] = nullable(v)
+	}
+	if ts.weights != nil {
+		raw.Weights = make([]*float64, len(ts.weights))
+		for i, w := range ts.weights {
+			raw.Weights[i] = nullable(w)
+		}
+	}
+	return json.Marshal(&raw)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (ts *TimeSeries) UnmarshalJSON(body []byte) error {
+	var raw timeSeriesJSON
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+
+	start, err := time.Parse(time.RFC3339Nano, raw.Start)
+	if err != nil {
+		return err
+	}
+	step, err := time.ParseDuration(raw.Step)
+	if err != nil {
+		return err
+	}
+
+	ts.key = raw.Key
+	ts.start = start
+	ts.step = step
+	ts.filler = unnullable(raw.Filler)
+	ts.weights = nil
+	if raw.Weights != nil {
+		ts.weights = make([]float64, len(raw.Weights))
+		for i, w := range raw.Weights {
+			ts.weights[i] = unnullable(w)
+		}
+	}
+	ts.version++
+	ts.data = make([]float64, len(raw.Data))
+	for i, v := range raw.Data {
+		ts.data[i] = unnullable(v)
+	}
+	return ts.Verify()
+}
+
+// EncodeSeriesStream writes the series received from the channel as a JSON
+// array until it is closed, without holding them all in memory. On the first
+// error the remaining series are drained so that the sender never blocks, and
+// the error is returned once the channel is closed.
+func EncodeSeriesStream(w io.Writer, series <-chan *TimeSeries) error {
+	err := encodeSeriesStream(w, series)
+	if err != nil {
+		for range series {
+		}
+	}
+	return err
+}
+
+func encodeSeriesStream(w io.Writer, series <-chan *TimeSeries) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	for ts := range series {
+		body, err := ts.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := w.Write(body); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// DecodeSeriesStream reads a JSON array of series as written by
+// EncodeSeriesStream and sends each one to the channel as soon as it is
+// decoded. The channel is closed when it returns.
+func DecodeSeriesStream(r io.Reader, series chan<- *TimeSeries) error {
+	defer close(series)
+
+	dec := json.NewDecoder(r)
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected the start of an array, got %v", token)
+	}
+
+	for dec.More() {
+		ts := &TimeSeries{}
+		if err := dec.Decode(ts); err != nil {
+			return err
+		}
+		series <- ts
+	}
+
+	_, err = dec.Token()
+	return err
+}
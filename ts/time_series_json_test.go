@@ -0,0 +1,124 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesJSON(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 2.5, NaN})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 2, 0)
+	checkErr(t, err)
+
+	for _, exp := range []*TimeSeries{ts0, ts1} {
+		body, err := json.Marshal(exp)
+		checkErr(t, err)
+		fmt.Println(string(body))
+
+		got := &TimeSeries{}
+		checkErr(t, json.Unmarshal(body, got))
+
+		checkTimeSeries(t, got, exp)
+		checkFloat(t, "filler", got.filler, exp.filler)
+	}
+
+	got := &TimeSeries{}
+	if err := json.Unmarshal([]byte(`{"key":"bad","start":"now","step":"1m"}`), got); err == nil {
+		t.Errorf("FAIL(start): expected error for invalid start")
+	}
+	if err := json.Unmarshal([]byte(`{"key":"bad","start":"2016-02-01T10:00:00Z","step":"0s","data":[]}`), got); err == nil {
+		t.Errorf("FAIL(step): expected error for a zero step")
+	}
+}
+
+func TestSeriesStream(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	exp := make([]*TimeSeries, 3)
+	for i := range exp {
+		ts, err := NewTimeSeriesOfData(fmt.Sprintf("test%d", i), start, step, []float64{float64(i), NaN})
+		checkErr(t, err)
+		exp[i] = ts
+	}
+
+	var buf bytes.Buffer
+	in := make(chan *TimeSeries)
+	go func() {
+		for _, ts := range exp {
+			in <- ts
+		}
+		close(in)
+	}()
+	checkErr(t, EncodeSeriesStream(&buf, in))
+	fmt.Println(buf.String())
+
+	var all []*TimeSeries
+	checkErr(t, json.Unmarshal(buf.Bytes(), &all))
+	if len(all) != len(exp) {
+		t.Errorf("FAIL(array): got: '%d' series, expected '%d'", len(all), len(exp))
+	}
+
+	out := make(chan *TimeSeries)
+	errs := make(chan error, 1)
+	go func() { errs <- DecodeSeriesStream(&buf, out) }()
+
+	var i int
+	for got := range out {
+		if i < len(exp) {
+			checkTimeSeries(t, got, exp[i])
+		}
+		i++
+	}
+	checkErr(t, <-errs)
+	if i != len(exp) {
+		t.Errorf("FAIL(stream): got: '%d' series, expected '%d'", i, len(exp))
+	}
+
+	empty := make(chan *TimeSeries)
+	close(empty)
+	buf.Reset()
+	checkErr(t, EncodeSeriesStream(&buf, empty))
+	if buf.String() != "[]" {
+		t.Errorf("FAIL(empty): got: '%s', expected '%s'", buf.String(), "[]")
+	}
+
+	out = make(chan *TimeSeries, 1)
+	if err := DecodeSeriesStream(bytes.NewBufferString(`{"key":"x"}`), out); err == nil {
+		t.Errorf("FAIL(decode): expected error for a non array")
+	}
+	in = make(chan *TimeSeries)
+	sent := make(chan struct{})
+	go func() {
+		for _, ts := range exp {
+			in <- ts
+		}
+		close(in)
+		close(sent)
+	}()
+	if err := EncodeSeriesStream(failingWriter{}, in); err == nil {
+		t.Errorf("FAIL(encode): expected error from the writer")
+	}
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Errorf("FAIL(encode): the sender is blocked")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
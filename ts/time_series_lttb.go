@@ -0,0 +1,116 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DownsampleLTTB reduces the series to at most threshold points for plotting
+// using the Largest-Triangle-Three-Buckets algorithm, which keeps the peaks
+// that averaging flattens. Points are grouped in buckets of equal length as
+// with Downsample, each bucket keeping the value of the point forming the
+// largest triangle with the point kept for the previous bucket and the mean of
+// the next bucket. The first and last buckets keep their first and last valid
+// points. A series of at most threshold points, or a threshold under 3, is
+// returned as a copy.
+func (ts *TimeSeries) DownsampleLTTB(threshold int) *TimeSeries {
+	n := len(ts.data)
+	if threshold < 3 || n <= threshold {
+		return ts.Copy()
+	}
+	factor := (n + threshold - 1) / threshold
+	buckets := (n + factor - 1) / factor
+
+	dts := &TimeSeries{
+		key:    fmt.Sprintf("LTTB(%d)(%s)", threshold, ts.key),
+		start:  ts.start,
+		step:   ts.step * time.Duration(factor),
+		data:   make([]float64, buckets),
+		filler: ts.filler,
+	}
+
+	bounds := func(b int) (int, int) {
+		to := (b + 1) * factor
+		if to > n {
+			to = n
+		}
+		return b * factor, to
+	}
+
+	// a is the index of the last kept point, -1 until one is found.
+	a := -1
+	for i := 0; i < factor; i++ {
+		if !math.IsNaN(ts.data[i]) {
+			a = i
+			break
+		}
+	}
+	dts.data[0] = math.NaN()
+	if a != -1 {
+		dts.data[0] = ts.data[a]
+	}
+
+	for b := 1; b < buckets-1; b++ {
+		from, to := bounds(b)
+
+		// The last bucket is represented by its last point.
+		nextFrom, nextTo := bounds(b + 1)
+		if b+1 == buckets-1 {
+			nextFrom = nextTo - 1
+		}
+		var avgX, avgY float64
+		var count int
+		for j := nextFrom; j < nextTo; j++ {
+			if !math.IsNaN(ts.data[j]) {
+				avgX += float64(j)
+				avgY += ts.data[j]
+				count++
+			}
+		}
+		avgX /= float64(count)
+		avgY /= float64(count)
+
+		best, bestArea := -1, -1.0
+		for j := from; j < to; j++ {
+			v := ts.data[j]
+			if math.IsNaN(v) {
+				continue
+			}
+			// Without a valid next bucket, keep the point furthest from the last
+			// kept value.
+			var area float64
+			switch {
+			case a == -1:
+				area = 0
+			case count == 0:
+				area = math.Abs(v - ts.data[a])
+			default:
+				xa, ya := float64(a), ts.data[a]
+				area = math.Abs((xa-avgX)*(v-ya) - (xa-float64(j))*(avgY-ya))
+			}
+			if area > bestArea {
+				best, bestArea = j, area
+			}
+		}
+
+		if best == -1 {
+			dts.data[b] = math.NaN()
+			continue
+		}
+		dts.data[b] = ts.data[best]
+		a = best
+	}
+
+	from, to := bounds(buckets - 1)
+	dts.data[buckets-1] = math.NaN()
+	for j := to - 1; j >= from; j-- {
+		if !math.IsNaN(ts.data[j]) {
+			dts.data[buckets-1] = ts.data[j]
+			break
+		}
+	}
+	return dts
+}
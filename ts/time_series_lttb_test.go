@@ -0,0 +1,56 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesDownsampleLTTB(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 0, 0, 9, 0, 0, 0, 0, -5, 0})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{NaN, 1, NaN, NaN, 2, 3, 4, NaN})
+	checkErr(t, err)
+
+	tests := []struct {
+		Got, Exp *TimeSeries
+	}{
+		{
+			Got: ts0.DownsampleLTTB(4),
+			Exp: &TimeSeries{
+				key:   "LTTB(4)(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{0, 9, -5, 0},
+			},
+		},
+		{
+			Got: ts1.DownsampleLTTB(4),
+			Exp: &TimeSeries{
+				key:   "LTTB(4)(test1)",
+				start: start,
+				step:  2 * step,
+				data:  []float64{1, NaN, 3, 4},
+			},
+		},
+		{
+			Got: ts0.DownsampleLTTB(10),
+			Exp: ts0,
+		},
+		{
+			Got: ts0.DownsampleLTTB(2),
+			Exp: ts0,
+		},
+	}
+
+	for _, test := range tests {
+		fmt.Printf("%s\n%s\n\n", test.Got, test.Exp)
+		checkTimeSeries(t, test.Got, test.Exp)
+	}
+}
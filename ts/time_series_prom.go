@@ -0,0 +1,133 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PromSample is a value scraped from Prometheus with its timestamp in
+// milliseconds since the epoch.
+type PromSample struct {
+	TimestampMs int64
+	Value       float64
+}
+
+func (sample PromSample) time() time.Time {
+	return time.Unix(0, sample.TimestampMs*int64(time.Millisecond)).UTC()
+}
+
+// NewTimeSeriesFromPromSamples places the samples onto a grid of step starting
+// at the earliest sample truncated to step, as NewTimeSeriesFromSamples does.
+func NewTimeSeriesFromPromSamples(key string, step time.Duration, samples []PromSample) (*TimeSeries, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples to bucket")
+	}
+
+	times := make([]time.Time, len(samples))
+	values := make([]float64, len(samples))
+	start := samples[0].time()
+	for i, sample := range samples {
+		times[i] = sample.time()
+		values[i] = sample.Value
+		if times[i].Before(start) {
+			start = times[i]
+		}
+	}
+	return NewTimeSeriesFromSamples(key, start.Truncate(step), step, times, values)
+}
+
+// WritePromText writes a 'name value timestampMs' line for each non NaN point
+// as in the Prometheus text exposition format. The name is the key with the
+// characters not allowed in metric names, such as the parentheses of derived
+// keys, replaced by underscores.
+func (ts *TimeSeries) WritePromText(w io.Writer) error {
+	name := promName(ts.key)
+
+	s := bytes.NewBufferString("")
+	ts.ForEach(func(i int, t time.Time, v float64) bool {
+		if math.IsNaN(v) {
+			return true
+		}
+		s.WriteString(name)
+		s.WriteByte(' ')
+		s.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		s.WriteByte(' ')
+		s.WriteString(strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10))
+		s.WriteByte('\n')
+		return true
+	})
+
+	_, err := s.WriteTo(w)
+	return err
+}
+
+// promName maps key to a valid metric name, matching [a-zA-Z_:][a-zA-Z0-9_:]*.
+func promName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		valid := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == ':' ||
+			i > 0 && c >= '0' && c <= '9'
+		if !valid {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 {
+		return "_"
+	}
+	return string(name)
+}
+
+// ReadPromText parses 'name value timestampMs' lines, such as the ones written
+// by WritePromText, into the samples of each metric. The labels within braces
+// are kept as part of the name, while blank lines and comments are skipped.
+// Samples without a timestamp are rejected.
+func ReadPromText(r io.Reader) (map[string][]PromSample, error) {
+	samples := make(map[string][]PromSample)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		split := strings.IndexAny(text, " \t")
+		if brace := strings.IndexByte(text, '{'); brace != -1 && (split == -1 || brace < split) {
+			end := strings.IndexByte(text, '}')
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: unterminated labels", line)
+			}
+			split = end + 1
+		}
+		if split <= 0 {
+			return nil, fmt.Errorf("line %d: missing value", line)
+		}
+		name := text[:split]
+
+		fields := strings.Fields(text[split:])
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a value and a timestamp, got %d fields", line, len(fields))
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		ms, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		samples[name] = append(samples[name], PromSample{ms, value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
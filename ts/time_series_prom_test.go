@@ -0,0 +1,87 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesPromSamples(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := 15 * time.Second
+	ms := start.UnixNano() / int64(time.Millisecond)
+
+	samples := []PromSample{
+		{ms + 16000, 2},
+		{ms + 1000, 1},
+		{ms + 44000, 3.5},
+	}
+
+	got, err := NewTimeSeriesFromPromSamples("up", step, samples)
+	checkErr(t, err)
+	exp := &TimeSeries{
+		key:   "up",
+		start: start,
+		step:  step,
+		data:  []float64{1, 2, NaN, 3.5},
+	}
+	fmt.Printf("%s\n%s\n\n", got, exp)
+	checkTimeSeries(t, got, exp)
+
+	var buf bytes.Buffer
+	checkErr(t, got.WritePromText(&buf))
+	text := fmt.Sprintf("up 1 %d\nup 2 %d\nup 3.5 %d\n", ms, ms+15000, ms+45000)
+	if buf.String() != text {
+		t.Errorf("FAIL(text): got:\n%s\nexpected:\n%s", buf.String(), text)
+	}
+
+	if _, err := NewTimeSeriesFromPromSamples("up", step, nil); err == nil {
+		t.Errorf("FAIL(samples): expected error without samples")
+	}
+}
+
+func TestTimeSeriesPromText(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := 15 * time.Second
+	ms := start.UnixNano() / int64(time.Millisecond)
+
+	ts0, err := NewTimeSeriesOfData("RollingMean(2m0s)(x.y)", start, step, []float64{1, NaN, 2.5, math.Inf(1)})
+	checkErr(t, err)
+
+	var buf bytes.Buffer
+	checkErr(t, ts0.WritePromText(&buf))
+	text := fmt.Sprintf("RollingMean_2m0s__x_y_ 1 %d\nRollingMean_2m0s__x_y_ 2.5 %d\nRollingMean_2m0s__x_y_ +Inf %d\n",
+		ms, ms+30000, ms+45000)
+	if buf.String() != text {
+		t.Errorf("FAIL(text): got:\n%s\nexpected:\n%s", buf.String(), text)
+	}
+
+	samples, err := ReadPromText(&buf)
+	checkErr(t, err)
+	got, err := NewTimeSeriesFromPromSamples("x", step, samples["RollingMean_2m0s__x_y_"])
+	checkErr(t, err)
+	ts0.SetKey("x")
+	fmt.Printf("%s\n%s\n\n", got, ts0)
+	checkTimeSeries(t, got, ts0)
+
+	samples, err = ReadPromText(strings.NewReader(
+		"# TYPE up gauge\n\nup{job=\"a b\"} 1 1000\nup 0 2000\nup{job=\"a b\"} NaN 3000\n"))
+	checkErr(t, err)
+	if n := len(samples["up{job=\"a b\"}"]); n != 2 {
+		t.Errorf("FAIL(samples): got: '%d', expected '%d'", n, 2)
+	}
+	if fmt.Sprint(samples["up"]) != fmt.Sprint([]PromSample{{2000, 0}}) {
+		t.Errorf("FAIL(samples): got: '%v', expected '%v'", samples["up"], []PromSample{{2000, 0}})
+	}
+
+	for _, bad := range []string{"up 1\n", "up one 1000\n", "up 1 soon\n", "up{job=\"a\" 1 1000\n", "up\n"} {
+		if _, err := ReadPromText(strings.NewReader(bad)); err == nil {
+			t.Errorf("FAIL(read): expected error for '%s'", bad)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// indexRange returns the indexes of the grid points covering [start, end),
+// clamped to the bounds of the series.
+func (ts *TimeSeries) indexRange(start, end time.Time) (int, int, error) {
+	if !start.Before(end) || !start.Before(ts.End()) || !end.After(ts.start) {
+		return 0, 0, fmt.Errorf("range [%v, %v) doesn't overlap [%v, %v)", start, end, ts.start, ts.End())
+	}
+
+	from := 0
+	if start.After(ts.start) {
+		from = int(start.Sub(ts.start) / ts.step)
+	}
+	to := len(ts.data)
+	if end.Before(ts.End()) {
+		distance := end.Sub(ts.start)
+		to = int(distance / ts.step)
+		if distance%ts.step != 0 {
+			to++
+		}
+	}
+	return from, to, nil
+}
+
+// Slice returns a copy of the points covering [start, end), snapped to the
+// grid of the series and clamped to its bounds.
+func (ts *TimeSeries) Slice(start, end time.Time) (*TimeSeries, error) {
+	from, to, err := ts.indexRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.sub(from, to), nil
+}
+
+// SubSeries is like Slice but returns an error unless [start, end) is entirely
+// within the series, rather than clamping it.
+func (ts *TimeSeries) SubSeries(start, end time.Time) (*TimeSeries, error) {
+	if start.Before(ts.start) || end.After(ts.End()) {
+		return nil, fmt.Errorf("range [%v, %v) isn't within [%v, %v)", start, end, ts.start, ts.End())
+	}
+	return ts.Slice(start, end)
+}
+
+// GetRange returns a copy of the values of the grid points covering
+// [start, end), clamped to the bounds of the series, without building a series
+// as Slice does.
+func (ts *TimeSeries) GetRange(start, end time.Time) ([]float64, error) {
+	from, to, err := ts.indexRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]float64, to-from)
+	copy(vals, ts.data[from:to])
+	return vals, nil
+}
+
+// SetRange writes values from the grid point of start onwards and returns how
+// many were written, the values past the end of the series being ignored.
+func (ts *TimeSeries) SetRange(start time.Time, values []float64) (int, error) {
+	if start.Before(ts.start) {
+		return 0, fmt.Errorf("start %v is before the series start %v", start, ts.start)
+	}
+	if !start.Before(ts.End()) {
+		return 0, nil
+	}
+	from := int(start.Sub(ts.start) / ts.step)
+	ts.version++
+	return copy(ts.data[from:], values), nil
+}
+
+// Chunk splits the series into consecutive copies of n points, the last one
+// holding the remaining points. It panics if n isn't positive.
+func (ts *TimeSeries) Chunk(n int) []*TimeSeries {
+	if n < 1 {
+		panic(fmt.Sprintf("chunk size %d must be positive", n))
+	}
+	chunks := make([]*TimeSeries, 0, (len(ts.data)+n-1)/n)
+	for from := 0; from < len(ts.data); from += n {
+		to := from + n
+		if to > len(ts.data) {
+			to = len(ts.data)
+		}
+		chunks = append(chunks, ts.sub(from, to))
+	}
+	return chunks
+}
+
+// sub returns a copy of the points within [from, to).
+func (ts *TimeSeries) sub(from, to int) *TimeSeries {
+	sts := &TimeSeries{
+		key:    ts.key,
+		start:  ts.TimeAt(from),
+		step:   ts.step,
+		data:   make([]float64, to-from),
+		filler: ts.filler,
+	}
+	copy(sts.data, ts.data[from:to])
+	if ts.weights != nil {
+		sts.weights = copyWeights(ts.weights[from:to])
+	}
+	return sts
+}
+
+// Trim returns a copy without the leading and trailing NaN values, moving the
+// start past the leading ones. A series of only NaN values becomes empty.
+func (ts *TimeSeries) Trim() *TimeSeries {
+	from := ts.firstValid()
+	if from == -1 {
+		return ts.sub(0, 0)
+	}
+	return ts.sub(from, ts.lastValid()+1)
+}
+
+// TrimTrailing returns a copy without the trailing NaN values.
+func (ts *TimeSeries) TrimTrailing() *TimeSeries {
+	return ts.sub(0, ts.lastValid()+1)
+}
+
+// FirstValid returns the first non NaN value and its time.
+func (ts *TimeSeries) FirstValid() (time.Time, float64, bool) {
+	return ts.pointAt(ts.firstValid())
+}
+
+// LastValid returns the last non NaN value and its time, unlike Iterator.Last
+// which returns the last point even if it's NaN.
+func (ts *TimeSeries) LastValid() (time.Time, float64, bool) {
+	return ts.pointAt(ts.lastValid())
+}
+
+// pointAt returns the point at index, or the zero time and NaN for -1.
+func (ts *TimeSeries) pointAt(index int) (time.Time, float64, bool) {
+	if index == -1 {
+		return time.Time{}, math.NaN(), false
+	}
+	return ts.TimeAt(index), ts.data[index], true
+}
+
+// firstValid returns the index of the first non NaN value, or -1.
+func (ts *TimeSeries) firstValid() int {
+	for i, v := range ts.data {
+		if !math.IsNaN(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastValid returns the index of the last non NaN value, or -1.
+func (ts *TimeSeries) lastValid() int {
+	i := len(ts.data) - 1
+	for i >= 0 && math.IsNaN(ts.data[i]) {
+		i--
+	}
+	return i
+}
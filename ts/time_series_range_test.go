@@ -0,0 +1,275 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesSlice(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 1, 2, 3, 4, 5})
+	checkErr(t, err)
+
+	slice := func(from, to time.Time) *TimeSeries {
+		got, err := ts0.Slice(from, to)
+		checkErr(t, err)
+		return got
+	}
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: slice(start.Add(2*step), start.Add(4*step)),
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start.Add(2 * step),
+				step:  step,
+				data:  []float64{2, 3},
+			},
+		},
+		{
+			Got: slice(start.Add(90*time.Second), start.Add(150*time.Second)),
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start.Add(step),
+				step:  step,
+				data:  []float64{1, 2},
+			},
+		},
+		{
+			Got: slice(start.Add(-time.Hour), start.Add(time.Hour)),
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start,
+				step:  step,
+				data:  []float64{0, 1, 2, 3, 4, 5},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+
+	if _, err := ts0.Slice(start.Add(time.Hour), start.Add(2*time.Hour)); err == nil {
+		t.Errorf("FAIL(range): expected error for non overlapping range")
+	}
+	if _, err := ts0.Slice(start.Add(-time.Hour), start); err == nil {
+		t.Errorf("FAIL(range): expected error for range ending at the start")
+	}
+}
+
+func TestTimeSeriesTrim(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{NaN, NaN, 1, NaN, 2, NaN})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 3, NaN)
+	checkErr(t, err)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ts0.Trim(),
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start.Add(2 * step),
+				step:  step,
+				data:  []float64{1, NaN, 2},
+			},
+		},
+		{
+			Got: ts0.TrimTrailing(),
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, NaN, 1, NaN, 2},
+			},
+		},
+		{
+			Got: ts1.Trim(),
+			Exp: &TimeSeries{
+				key:   "test1",
+				start: start,
+				step:  step,
+				data:  []float64{},
+			},
+		},
+		{
+			Got: ts1.TrimTrailing(),
+			Exp: &TimeSeries{
+				key:   "test1",
+				start: start,
+				step:  step,
+				data:  []float64{},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+		checkErr(t, pair.Got.Verify())
+	}
+}
+
+func TestTimeSeriesGetRange(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 1, NaN, 3, 4})
+	checkErr(t, err)
+
+	got, err := ts0.GetRange(start.Add(step), start.Add(3*step))
+	checkErr(t, err)
+	checkData(t, got, []float64{1, NaN})
+
+	got, err = ts0.GetRange(start.Add(3*step), start.Add(time.Hour))
+	checkErr(t, err)
+	checkData(t, got, []float64{3, 4})
+
+	got[0] = 42
+	if v, _ := ts0.GetAt(start.Add(3 * step)); v != 3 {
+		t.Errorf("FAIL(copy): got: '%f', expected '%f'", v, 3.0)
+	}
+
+	if _, err := ts0.GetRange(start.Add(-time.Hour), start); err == nil {
+		t.Errorf("FAIL(range): expected error for a range outside the series")
+	}
+}
+
+func TestTimeSeriesFirstLastValid(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{NaN, 1, NaN, 2, NaN, NaN})
+	checkErr(t, err)
+
+	first, v, ok := ts0.FirstValid()
+	if !ok || v != 1 {
+		t.Errorf("FAIL(first): got: '%f', '%v', expected '%f'", v, ok, 1.0)
+	}
+	checkTime(t, "first", first, start.Add(step))
+
+	last, v, ok := ts0.LastValid()
+	if !ok || v != 2 {
+		t.Errorf("FAIL(last): got: '%f', '%v', expected '%f'", v, ok, 2.0)
+	}
+	checkTime(t, "last", last, start.Add(3*step))
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 2, NaN)
+	checkErr(t, err)
+	if _, _, ok := ts1.FirstValid(); ok {
+		t.Errorf("FAIL(first): expected no valid value")
+	}
+	if _, _, ok := ts1.LastValid(); ok {
+		t.Errorf("FAIL(last): expected no valid value")
+	}
+}
+
+func TestTimeSeriesSetRange(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfLength("test0", start, step, 5, NaN)
+	checkErr(t, err)
+
+	n, err := ts0.SetRange(start.Add(90*time.Second), []float64{1, 2})
+	checkErr(t, err)
+	if n != 2 {
+		t.Errorf("FAIL(written): got: '%d', expected '%d'", n, 2)
+	}
+
+	n, err = ts0.SetRange(start.Add(4*step), []float64{3, 4, 5})
+	checkErr(t, err)
+	if n != 1 {
+		t.Errorf("FAIL(written): got: '%d', expected '%d'", n, 1)
+	}
+	checkData(t, ts0.data, []float64{NaN, 1, 2, NaN, 3})
+
+	n, err = ts0.SetRange(start.Add(time.Hour), []float64{6})
+	checkErr(t, err)
+	if n != 0 {
+		t.Errorf("FAIL(written): got: '%d', expected '%d'", n, 0)
+	}
+
+	if _, err := ts0.SetRange(start.Add(-step), []float64{7}); err == nil {
+		t.Errorf("FAIL(start): expected error for a start before the series")
+	}
+}
+
+func TestTimeSeriesChunk(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 1, 2, 3, 4})
+	checkErr(t, err)
+
+	chunks := ts0.Chunk(2)
+	exp := []*TimeSeries{
+		{key: "test0", start: start, step: step, data: []float64{0, 1}},
+		{key: "test0", start: start.Add(2 * step), step: step, data: []float64{2, 3}},
+		{key: "test0", start: start.Add(4 * step), step: step, data: []float64{4}},
+	}
+	if len(chunks) != len(exp) {
+		t.Fatalf("FAIL(chunks): got: '%d', expected '%d'", len(chunks), len(exp))
+	}
+	for i := range exp {
+		fmt.Printf("%s\n%s\n\n", chunks[i], exp[i])
+		checkTimeSeries(t, chunks[i], exp[i])
+		checkErr(t, chunks[i].Verify())
+	}
+
+	if got := ts0.Chunk(10); len(got) != 1 || !got[0].Equal(ts0) {
+		t.Errorf("FAIL(chunks): expected a single chunk equal to the series")
+	}
+	empty, err := NewTimeSeriesOfData("empty", start, step, []float64{})
+	checkErr(t, err)
+	if got := empty.Chunk(3); len(got) != 0 {
+		t.Errorf("FAIL(chunks): got: '%d', expected no chunks", len(got))
+	}
+}
+
+func TestTimeSeriesSubSeries(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 1, 2, 3})
+	checkErr(t, err)
+
+	got, err := ts0.SubSeries(start.Add(step), start.Add(3*step))
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "test0",
+		start: start.Add(step),
+		step:  step,
+		data:  []float64{1, 2},
+	})
+
+	got, err = ts0.SubSeries(start, ts0.End())
+	checkErr(t, err)
+	checkTimeSeries(t, got, ts0)
+
+	for _, r := range [][2]time.Time{
+		{start.Add(-step), start.Add(2 * step)},
+		{start.Add(step), start.Add(5 * step)},
+		{start.Add(2 * step), start.Add(step)},
+	} {
+		if _, err := ts0.SubSeries(r[0], r[1]); err == nil {
+			t.Errorf("FAIL(range): expected error for [%v, %v)", r[0], r[1])
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// LinearFit returns the least squares line fitting the non NaN values against
+// their offset from the start, expressed in steps.
+func (ts *TimeSeries) LinearFit() (slope, intercept float64, err error) {
+	var sumX, sumY, sumXX, sumXY float64
+	var n int
+	for i, y := range ts.data {
+		if math.IsNaN(y) {
+			continue
+		}
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+		n++
+	}
+	if n < 2 {
+		return math.NaN(), math.NaN(), errors.New("less than two valid points")
+	}
+
+	count := float64(n)
+	slope = (count*sumXY - sumX*sumY) / (count*sumXX - sumX*sumX)
+	intercept = (sumY - slope*sumX) / count
+	return slope, intercept, nil
+}
+
+// Predict evaluates the line returned by LinearFit at time t, which doesn't
+// need to be on the grid or within the series. NaN is returned if the series
+// can't be fitted.
+func (ts *TimeSeries) Predict(t time.Time) float64 {
+	slope, intercept, err := ts.LinearFit()
+	if err != nil {
+		return math.NaN()
+	}
+	return intercept + slope*ts.offset(t)
+}
+
+// Detrend returns a copy where the line returned by LinearFit is subtracted
+// from each value, leaving the residuals. As with Predict, every value is NaN
+// if the series can't be fitted.
+func (ts *TimeSeries) Detrend() *TimeSeries {
+	slope, intercept, err := ts.LinearFit()
+
+	dts := ts.Copy()
+	dts.key = "Detrend(" + ts.key + ")"
+	for i, v := range dts.data {
+		if err != nil {
+			dts.data[i] = math.NaN()
+			continue
+		}
+		dts.data[i] = v - (intercept + slope*float64(i))
+	}
+	return dts
+}
+
+// ForecastHolt extrapolates steps points past the end using Holt's linear
+// exponential smoothing, alpha smoothing the level and beta the trend, both
+// within (0, 1]. The level is seeded from the first non NaN value and the trend
+// from the slope to the second one. NaN values afterwards are replaced by the
+// one step forecast. The forecast is returned as a series starting at End.
+func (ts *TimeSeries) ForecastHolt(alpha, beta float64, steps int) (*TimeSeries, error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, fmt.Errorf("alpha %f is not within (0, 1]", alpha)
+	}
+	if !(beta > 0 && beta <= 1) {
+		return nil, fmt.Errorf("beta %f is not within (0, 1]", beta)
+	}
+	if steps < 1 {
+		return nil, fmt.Errorf("steps %d must be at least 1", steps)
+	}
+
+	first := ts.firstValid()
+	second := -1
+	for i := first + 1; first != -1 && i < len(ts.data); i++ {
+		if !math.IsNaN(ts.data[i]) {
+			second = i
+			break
+		}
+	}
+	if second == -1 {
+		return nil, errors.New("less than two valid points")
+	}
+
+	level := ts.data[first]
+	trend := (ts.data[second] - level) / float64(second-first)
+	for _, v := range ts.data[first+1:] {
+		if math.IsNaN(v) {
+			level += trend
+			continue
+		}
+		prev := level
+		level = alpha*v + (1-alpha)*(level+trend)
+		trend = beta*(level-prev) + (1-beta)*trend
+	}
+
+	fts := &TimeSeries{
+		key:    fmt.Sprintf("ForecastHolt(%f,%f)(%s)", alpha, beta, ts.key),
+		start:  ts.End(),
+		step:   ts.step,
+		data:   make([]float64, steps),
+		filler: ts.filler,
+	}
+	for i := range fts.data {
+		fts.data[i] = level + float64(i+1)*trend
+	}
+	return fts, nil
+}
+
+// offset returns the distance between the start and t in steps.
+func (ts *TimeSeries) offset(t time.Time) float64 {
+	return float64(t.Sub(ts.start)) / float64(ts.step)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesLinearFit(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 3, NaN, 7, 9})
+	checkErr(t, err)
+
+	slope, intercept, err := ts0.LinearFit()
+	checkErr(t, err)
+	checkFloat(t, "slope", slope, 2)
+	checkFloat(t, "intercept", intercept, 1)
+
+	checkFloat(t, "predict", ts0.Predict(start.Add(2*step)), 5)
+	checkFloat(t, "predict", ts0.Predict(start.Add(10*step)), 21)
+	checkFloat(t, "predict", ts0.Predict(start.Add(90*time.Second)), 4)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{NaN, 1, NaN})
+	checkErr(t, err)
+
+	if _, _, err := ts1.LinearFit(); err == nil {
+		t.Errorf("FAIL(fit): expected error for a single valid point")
+	}
+	checkFloat(t, "predict", ts1.Predict(start), NaN)
+}
+
+func TestTimeSeriesDetrend(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{2, 2, NaN, 8, 8})
+	checkErr(t, err)
+
+	got := ts0.Detrend()
+	exp := &TimeSeries{
+		key:   "Detrend(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{0.6, -1.2, NaN, 1.2, -0.6},
+	}
+	if !got.EqualApprox(exp, 1e-9) {
+		t.Errorf("FAIL(Detrend): got:\n\t%s\nexpected:\n\t%s", got, exp)
+	}
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{NaN, 3})
+	checkErr(t, err)
+	checkTimeSeries(t, ts1.Detrend(), &TimeSeries{
+		key:   "Detrend(test1)",
+		start: start,
+		step:  step,
+		data:  []float64{NaN, NaN},
+	})
+}
+
+func TestTimeSeriesForecastHolt(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{NaN, 1, NaN, 3, 4})
+	checkErr(t, err)
+
+	got, err := ts0.ForecastHolt(0.3, 0.7, 2)
+	checkErr(t, err)
+	exp := &TimeSeries{
+		key:   "ForecastHolt(0.300000,0.700000)(test0)",
+		start: ts0.End(),
+		step:  step,
+		data:  []float64{5, 6},
+	}
+	if !got.EqualApprox(exp, 1e-9) {
+		t.Errorf("FAIL(ForecastHolt): got:\n\t%s\nexpected:\n\t%s", got, exp)
+	}
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{1, 3, 4})
+	checkErr(t, err)
+
+	got, err = ts1.ForecastHolt(0.5, 0.5, 2)
+	checkErr(t, err)
+	checkData(t, got.data, []float64{6.25, 8})
+
+	for _, args := range [][2]float64{{0, 0.5}, {0.5, 0}, {1.5, 0.5}, {0.5, NaN}} {
+		if _, err := ts1.ForecastHolt(args[0], args[1], 1); err == nil {
+			t.Errorf("FAIL(forecast): expected error for alpha %f and beta %f", args[0], args[1])
+		}
+	}
+	if _, err := ts1.ForecastHolt(0.5, 0.5, 0); err == nil {
+		t.Errorf("FAIL(forecast): expected error for no steps")
+	}
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{NaN, 1, NaN})
+	checkErr(t, err)
+	if _, err := ts2.ForecastHolt(0.5, 0.5, 1); err == nil {
+		t.Errorf("FAIL(forecast): expected error for a single valid point")
+	}
+}
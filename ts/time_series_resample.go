@@ -0,0 +1,274 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Downsample returns a series at the coarser step where each point aggregates
+// the non NaN values of the points in [bucketStart, bucketStart+step). Empty
+// buckets are set to the filler. The new step must be a multiple of the
+// current one.
+func (ts *TimeSeries) Downsample(step time.Duration, agg Aggregator) (*TimeSeries, error) {
+	if step < ts.step || step%ts.step != 0 {
+		return nil, fmt.Errorf("step %v is not a multiple of %v", step, ts.step)
+	}
+	factor := int(step / ts.step)
+
+	size := len(ts.data) / factor
+	if len(ts.data)%factor != 0 {
+		size++
+	}
+	dts := &TimeSeries{
+		key:    fmt.Sprintf("Downsample(%v,%s)(%s)", step, agg.Name(), ts.key),
+		start:  ts.start,
+		step:   step,
+		data:   make([]float64, size),
+		filler: ts.filler,
+	}
+
+	bucket := make([]float64, 0, factor)
+	for i := range dts.data {
+		bucket = bucket[:0]
+		for j := i * factor; j < (i+1)*factor && j < len(ts.data); j++ {
+			if v := ts.data[j]; !math.IsNaN(v) {
+				bucket = append(bucket, v)
+			}
+		}
+		if len(bucket) == 0 {
+			dts.data[i] = ts.filler
+			continue
+		}
+		dts.data[i] = agg.Aggregate(bucket)
+	}
+	return dts, nil
+}
+
+// DownsampleWithCounts is like Downsample but also returns a parallel series
+// holding the number of non NaN values in each bucket, zero when empty, so
+// that sparsely populated buckets can be told apart.
+func (ts *TimeSeries) DownsampleWithCounts(step time.Duration, agg Aggregator) (*TimeSeries, *TimeSeries, error) {
+	dts, err := ts.Downsample(step, agg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cts := ts.Copy()
+	cts.filler = 0
+	counts, err := cts.Downsample(step, &countAggregator{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return dts, counts, nil
+}
+
+// DownsampleAligned is like Downsample but the buckets start at the start of
+// the series truncated to a multiple of boundary, such as the top of the hour,
+// rather than at the start itself. The points of the series must fall on that
+// edge, that is its start must be a whole number of steps from it.
+func (ts *TimeSeries) DownsampleAligned(step, boundary time.Duration, agg Aggregator) (*TimeSeries, error) {
+	if boundary <= 0 {
+		return nil, fmt.Errorf("boundary %v must be positive", boundary)
+	}
+	edge := ts.start.Truncate(boundary)
+	offset := ts.start.Sub(edge)
+	if offset%ts.step != 0 {
+		return nil, fmt.Errorf("start %v is not a multiple of %v from the edge %v", ts.start, ts.step, edge)
+	}
+
+	padding := int(offset / ts.step)
+	pts := &TimeSeries{
+		key:    ts.key,
+		start:  edge,
+		step:   ts.step,
+		data:   make([]float64, padding, padding+len(ts.data)),
+		filler: ts.filler,
+	}
+	for i := range pts.data {
+		pts.data[i] = math.NaN()
+	}
+	pts.data = append(pts.data, ts.data...)
+	return pts.Downsample(step, agg)
+}
+
+// AlignTo brings the series to step so that it can be combined with a series
+// of that step. Only downsampling is supported, a series already at step is
+// copied as is.
+func (ts *TimeSeries) AlignTo(step time.Duration, agg Aggregator) (*TimeSeries, error) {
+	if step == ts.step {
+		return ts.Copy(), nil
+	}
+	if step < ts.step {
+		return nil, fmt.Errorf("step %v is finer than %v, upsampling is required", step, ts.step)
+	}
+	return ts.Downsample(step, agg)
+}
+
+// DownsampleRate downsamples a series of per second rates, such as the output
+// of Rate, so that the total over each bucket is preserved: each point is the
+// sum of the increases within its bucket divided by the new step. NaN points
+// count as no increase, so the rate isn't overstated as a mean would.
+func (ts *TimeSeries) DownsampleRate(step time.Duration) (*TimeSeries, error) {
+	return ts.Downsample(step, &rateAggregator{ratio: ts.step.Seconds() / step.Seconds()})
+}
+
+// Upsample returns a series at the finer step where the points between two
+// original points are linearly interpolated. An interval bounded by a NaN is
+// NaN, as are the points after the last original point since they have no
+// upper bound. The new step must divide the current one.
+func (ts *TimeSeries) Upsample(step time.Duration) (*TimeSeries, error) {
+	if step <= 0 || step > ts.step || ts.step%step != 0 {
+		return nil, fmt.Errorf("step %v doesn't divide %v", step, ts.step)
+	}
+	factor := int(ts.step / step)
+
+	uts := &TimeSeries{
+		key:    fmt.Sprintf("Upsample(%v)(%s)", step, ts.key),
+		start:  ts.start,
+		step:   step,
+		data:   make([]float64, len(ts.data)*factor),
+		filler: ts.filler,
+	}
+
+	for i, from := range ts.data {
+		to := math.NaN()
+		if i+1 < len(ts.data) {
+			to = ts.data[i+1]
+		}
+		uts.data[i*factor] = from
+		for j := 1; j < factor; j++ {
+			uts.data[i*factor+j] = from + (to-from)*float64(j)/float64(factor)
+		}
+	}
+	return uts, nil
+}
+
+// Aggregator reduces the non NaN values of a bucket to a single value, it is
+// never given an empty bucket.
+type Aggregator interface {
+	Name() string
+	Aggregate([]float64) float64
+}
+
+type SumAggregator struct{}
+
+func (agg *SumAggregator) Name() string {
+	return "Sum"
+}
+
+func (agg *SumAggregator) Aggregate(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum
+}
+
+type MeanAggregator struct{}
+
+func (agg *MeanAggregator) Name() string {
+	return "Mean"
+}
+
+func (agg *MeanAggregator) Aggregate(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
+
+type MinAggregator struct{}
+
+func (agg *MinAggregator) Name() string {
+	return "Min"
+}
+
+func (agg *MinAggregator) Aggregate(vals []float64) float64 {
+	min := vals[0]
+	for _, v := range vals[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+type MaxAggregator struct{}
+
+func (agg *MaxAggregator) Name() string {
+	return "Max"
+}
+
+func (agg *MaxAggregator) Aggregate(vals []float64) float64 {
+	max := vals[0]
+	for _, v := range vals[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+type LastAggregator struct{}
+
+func (agg *LastAggregator) Name() string {
+	return "Last"
+}
+
+func (agg *LastAggregator) Aggregate(vals []float64) float64 {
+	return vals[len(vals)-1]
+}
+
+// QuantileAggregator returns the Q quantile of each bucket, such as 0.99 for
+// latency rollups, interpolating as Quantile does. A Q outside of [0, 1]
+// yields NaN.
+type QuantileAggregator struct {
+	Q float64
+}
+
+func (agg *QuantileAggregator) Name() string {
+	return fmt.Sprintf("Quantile(%f)", agg.Q)
+}
+
+func (agg *QuantileAggregator) Aggregate(vals []float64) float64 {
+	if !(agg.Q >= 0 && agg.Q <= 1) {
+		return math.NaN()
+	}
+	sorted := make([]float64, len(vals))
+	copy(sorted, vals)
+	sort.Float64s(sorted)
+	return quantile(sorted, agg.Q)
+}
+
+// countAggregator counts the values of a bucket.
+type countAggregator struct{}
+
+func (agg *countAggregator) Name() string {
+	return "Count"
+}
+
+func (agg *countAggregator) Aggregate(vals []float64) float64 {
+	return float64(len(vals))
+}
+
+// rateAggregator sums per second rates scaled by the ratio between steps.
+type rateAggregator struct {
+	ratio float64
+}
+
+func (agg *rateAggregator) Name() string {
+	return "Rate"
+}
+
+func (agg *rateAggregator) Aggregate(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum * agg.ratio
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesDownsample(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step,
+		[]float64{1, 2, 3, NaN, NaN, NaN, 4, NaN, 8, 5})
+	checkErr(t, err)
+
+	down := func(agg Aggregator) *TimeSeries {
+		got, err := ts0.Downsample(3*step, agg)
+		checkErr(t, err)
+		return got
+	}
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: down(&SumAggregator{}),
+			Exp: &TimeSeries{
+				key:   "Downsample(3m0s,Sum)(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{6, NaN, 12, 5},
+			},
+		},
+		{
+			Got: down(&MeanAggregator{}),
+			Exp: &TimeSeries{
+				key:   "Downsample(3m0s,Mean)(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{2, NaN, 6, 5},
+			},
+		},
+		{
+			Got: down(&MinAggregator{}),
+			Exp: &TimeSeries{
+				key:   "Downsample(3m0s,Min)(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{1, NaN, 4, 5},
+			},
+		},
+		{
+			Got: down(&MaxAggregator{}),
+			Exp: &TimeSeries{
+				key:   "Downsample(3m0s,Max)(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{3, NaN, 8, 5},
+			},
+		},
+		{
+			Got: down(&QuantileAggregator{0.5}),
+			Exp: &TimeSeries{
+				key:   "Downsample(3m0s,Quantile(0.500000))(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{2, NaN, 6, 5},
+			},
+		},
+		{
+			Got: down(&QuantileAggregator{0.99}),
+			Exp: &TimeSeries{
+				key:   "Downsample(3m0s,Quantile(0.990000))(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{2.98, NaN, 7.96, 5},
+			},
+		},
+		{
+			Got: down(&QuantileAggregator{2}),
+			Exp: &TimeSeries{
+				key:   "Downsample(3m0s,Quantile(2.000000))(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{NaN, NaN, NaN, NaN},
+			},
+		},
+		{
+			Got: down(&LastAggregator{}),
+			Exp: &TimeSeries{
+				key:   "Downsample(3m0s,Last)(test0)",
+				start: start,
+				step:  3 * step,
+				data:  []float64{3, NaN, 8, 5},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+
+	if _, err := ts0.Downsample(90*time.Second, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(step): expected error for a step that isn't a multiple")
+	}
+	if _, err := ts0.Downsample(30*time.Second, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(step): expected error for a smaller step")
+	}
+}
+
+func TestTimeSeriesUpsample(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 4, NaN, 8, 4})
+	checkErr(t, err)
+
+	got, err := ts0.Upsample(15 * time.Second)
+	checkErr(t, err)
+
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "Upsample(15s)(test0)",
+		start: start,
+		step:  15 * time.Second,
+		data: []float64{
+			0, 1, 2, 3,
+			4, NaN, NaN, NaN,
+			NaN, NaN, NaN, NaN,
+			8, 7, 6, 5,
+			4, NaN, NaN, NaN,
+		},
+	})
+	fmt.Println(got)
+
+	if _, err := ts0.Upsample(25 * time.Second); err == nil {
+		t.Errorf("FAIL(step): expected error for a step that doesn't divide")
+	}
+	if _, err := ts0.Upsample(2 * time.Minute); err == nil {
+		t.Errorf("FAIL(step): expected error for a larger step")
+	}
+}
+
+func TestTimeSeriesAlignTo(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+
+	fine, err := NewTimeSeriesOfData("fine", start, 30*time.Second,
+		[]float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2})
+	checkErr(t, err)
+
+	coarse, err := NewTimeSeriesOfData("coarse", start, 5*time.Minute, []float64{10, 20})
+	checkErr(t, err)
+
+	aligned, err := fine.AlignTo(coarse.Step(), &SumAggregator{})
+	checkErr(t, err)
+
+	sum, err := aligned.Add(coarse)
+	checkErr(t, err)
+	checkTimeSeries(t, sum, &TimeSeries{
+		key:   "Add(Downsample(5m0s,Sum)(fine),coarse)",
+		start: start,
+		step:  5 * time.Minute,
+		data:  []float64{20, 40},
+	})
+
+	same, err := coarse.AlignTo(coarse.Step(), &SumAggregator{})
+	checkErr(t, err)
+	checkTimeSeries(t, same, coarse)
+
+	if _, err := coarse.AlignTo(30*time.Second, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(step): expected error when upsampling is required")
+	}
+}
+
+func TestTimeSeriesDownsampleRate(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 1, 2, 2, NaN, 6, NaN, NaN})
+	checkErr(t, err)
+
+	got, err := ts0.DownsampleRate(2 * step)
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "Downsample(2m0s,Rate)(test0)",
+		start: start,
+		step:  2 * step,
+		data:  []float64{1, 2, 3, NaN},
+	})
+
+	if _, err := ts0.DownsampleRate(90 * time.Second); err == nil {
+		t.Errorf("FAIL(step): expected error for a step that isn't a multiple")
+	}
+}
+
+func TestTimeSeriesDownsampleAligned(t *testing.T) {
+	edge := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	start := edge.Add(40 * time.Minute)
+	step := 10 * time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3, 4, 5, 6, 7, 8})
+	checkErr(t, err)
+
+	got, err := ts0.DownsampleAligned(time.Hour, time.Hour, &SumAggregator{})
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "Downsample(1h0m0s,Sum)(test0)",
+		start: edge,
+		step:  time.Hour,
+		data:  []float64{3, 33},
+	})
+
+	got, err = ts0.DownsampleAligned(30*time.Minute, time.Hour, &MeanAggregator{})
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "Downsample(30m0s,Mean)(test0)",
+		start: edge,
+		step:  30 * time.Minute,
+		data:  []float64{NaN, 1.5, 4, 7},
+	})
+
+	ts1, err := NewTimeSeriesOfData("test1", edge.Add(time.Minute), step, []float64{1, 2})
+	checkErr(t, err)
+	if _, err := ts1.DownsampleAligned(time.Hour, time.Hour, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(edge): expected error for points not on the edge")
+	}
+	if _, err := ts0.DownsampleAligned(time.Hour, 0, &SumAggregator{}); err == nil {
+		t.Errorf("FAIL(boundary): expected error for a zero boundary")
+	}
+}
+
+func TestTimeSeriesDownsampleWithCounts(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step,
+		[]float64{1, 2, 3, NaN, NaN, NaN, 4, NaN, 8, 5})
+	checkErr(t, err)
+
+	got, counts, err := ts0.DownsampleWithCounts(3*step, &MeanAggregator{})
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "Downsample(3m0s,Mean)(test0)",
+		start: start,
+		step:  3 * step,
+		data:  []float64{2, NaN, 6, 5},
+	})
+	checkTimeSeries(t, counts, &TimeSeries{
+		key:   "Downsample(3m0s,Count)(test0)",
+		start: start,
+		step:  3 * step,
+		data:  []float64{3, 0, 2, 1},
+	})
+
+	if _, _, err := ts0.DownsampleWithCounts(90*time.Second, &MeanAggregator{}); err == nil {
+		t.Errorf("FAIL(error): expected an error for a misaligned step")
+	}
+}
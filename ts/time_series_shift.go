@@ -0,0 +1,61 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reverse returns a copy covering the same time range with the data in the
+// reverse order, so that the last point becomes the value at the start.
+func (ts *TimeSeries) Reverse() *TimeSeries {
+	rts := ts.Copy()
+	rts.key = "Reverse(" + ts.key + ")"
+
+	for i, j := 0, len(rts.data)-1; i < j; i, j = i+1, j-1 {
+		rts.data[i], rts.data[j] = rts.data[j], rts.data[i]
+		if rts.weights != nil {
+			rts.weights[i], rts.weights[j] = rts.weights[j], rts.weights[i]
+		}
+	}
+	return rts
+}
+
+// SnapStart returns a copy whose start is truncated down to a multiple of to,
+// moving every point back by the same amount. The key is left untouched.
+func (ts *TimeSeries) SnapStart(to time.Duration) *TimeSeries {
+	return ts.Rebase(ts.start.Truncate(to))
+}
+
+// Rebase returns a copy starting at start with the same data and step, moving
+// every point in time by the same amount. The key is left untouched.
+func (ts *TimeSeries) Rebase(start time.Time) *TimeSeries {
+	rts := ts.Copy()
+	rts.start = start
+	return rts
+}
+
+// Shift returns a copy over the same time range where the values are moved n
+// steps later, or earlier when n is negative. Values moved past either end are
+// dropped and the vacated points are set to the filler.
+func (ts *TimeSeries) Shift(n int) *TimeSeries {
+	sts := ts.Copy()
+	sts.key = fmt.Sprintf("Shift(%d)(%s)", n, ts.key)
+
+	for i := range sts.data {
+		j := i - n
+		if j < 0 || j >= len(ts.data) {
+			sts.data[i] = ts.filler
+			if sts.weights != nil {
+				sts.weights[i] = 1
+			}
+			continue
+		}
+		sts.data[i] = ts.data[j]
+		if sts.weights != nil {
+			sts.weights[i] = ts.weights[j]
+		}
+	}
+	return sts
+}
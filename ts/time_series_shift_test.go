@@ -0,0 +1,137 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesReverse(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, NaN, 4})
+	checkErr(t, err)
+
+	reversed := ts0.Reverse()
+	checkTimeSeries(t, reversed, &TimeSeries{
+		key:   "Reverse(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{4, NaN, 2, 1},
+	})
+	if v, _ := reversed.GetAt(reversed.Start()); v != 4 {
+		t.Errorf("FAIL(GetAt): got: '%f', expected '%f'", v, 4.0)
+	}
+
+	twice := reversed.Reverse()
+	twice.SetKey(ts0.Key())
+	fmt.Printf("%s\n%s\n\n", twice, ts0)
+	checkTimeSeries(t, twice, ts0)
+}
+
+func TestTimeSeriesSnapStart(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 12, 345, time.UTC)
+	snapped := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3})
+	checkErr(t, err)
+
+	got := ts0.SnapStart(time.Minute)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "test0",
+		start: snapped,
+		step:  step,
+		data:  []float64{1, 2, 3},
+	})
+	if v, ok := got.GetAt(snapped.Add(step)); !ok || v != 2 {
+		t.Errorf("FAIL(GetAt): got: '%f', '%v', expected '%f'", v, ok, 2.0)
+	}
+
+	ts1, err := NewSnappedTimeSeries("test1", start, start.Add(3*step), step, NaN)
+	checkErr(t, err)
+	checkTimeSeries(t, ts1, &TimeSeries{
+		key:   "test1",
+		start: snapped,
+		step:  step,
+		data:  []float64{NaN, NaN, NaN},
+	})
+
+	ts2, err := NewSnappedTimeSeries("test2", start, time.Time{}, step, 4, 5)
+	checkErr(t, err)
+	checkTimeSeries(t, ts2, &TimeSeries{
+		key:   "test2",
+		start: snapped,
+		step:  step,
+		data:  []float64{4, 5},
+	})
+}
+
+func TestTimeSeriesRebase(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	rebased := time.Date(2016, time.Month(1), 1, 0, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 3})
+	checkErr(t, err)
+
+	got := ts0.Rebase(rebased)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "test0",
+		start: rebased,
+		step:  step,
+		data:  []float64{1, NaN, 3},
+	})
+	checkTime(t, "end", got.End(), rebased.Add(3*step))
+	checkTime(t, "start", ts0.Start(), start)
+}
+
+func TestTimeSeriesShift(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3, 4})
+	checkErr(t, err)
+	ts0.SetFiller(NaN, false)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ts0.Shift(1),
+			Exp: &TimeSeries{
+				key:   "Shift(1)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 1, 2, 3},
+			},
+		},
+		{
+			Got: ts0.Shift(-2),
+			Exp: &TimeSeries{
+				key:   "Shift(-2)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{3, 4, NaN, NaN},
+			},
+		},
+		{
+			Got: ts0.Shift(10),
+			Exp: &TimeSeries{
+				key:   "Shift(10)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, NaN, NaN, NaN},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
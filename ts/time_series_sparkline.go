@@ -0,0 +1,68 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"math"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the series as width block characters, each one being the
+// mean of the points within its column scaled between the min and the max of
+// the finite columns. Infinite columns are rendered as the highest or lowest
+// block and columns without valid points as spaces.
+func (ts *TimeSeries) Sparkline(width int) string {
+	if width < 1 || len(ts.data) == 0 {
+		return ""
+	}
+
+	columns := make([]float64, width)
+	for c := range columns {
+		from := c * len(ts.data) / width
+		to := (c + 1) * len(ts.data) / width
+		if to <= from {
+			to = from + 1
+		}
+
+		var sum float64
+		var count int
+		for _, v := range ts.data[from:to] {
+			if !math.IsNaN(v) {
+				sum += v
+				count++
+			}
+		}
+		if count == 0 {
+			columns[c] = math.NaN()
+			continue
+		}
+		columns[c] = sum / float64(count)
+	}
+	min, max, _ := finiteRange(columns)
+
+	s := bytes.NewBufferString("")
+	for _, v := range columns {
+		if math.IsNaN(v) {
+			s.WriteByte(' ')
+			continue
+		}
+		index := 0
+		switch {
+		case math.IsInf(v, 1):
+			index = len(sparks) - 1
+		case math.IsInf(v, -1):
+		case max > min:
+			index = int((v - min) / (max - min) * float64(len(sparks)-1))
+		}
+		if index < 0 {
+			index = 0
+		}
+		if index >= len(sparks) {
+			index = len(sparks) - 1
+		}
+		s.WriteRune(sparks[index])
+	}
+	return s.String()
+}
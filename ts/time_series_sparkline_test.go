@@ -0,0 +1,54 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesSparkline(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 1, 2, 3, 4, 5, 6, 7})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{0, 0, NaN, NaN, 7, 7})
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfLength("test2", start, step, 4, 3)
+	checkErr(t, err)
+
+	ts3, err := NewTimeSeriesOfData("test3", start, step, []float64{0, 1, math.Inf(1)})
+	checkErr(t, err)
+
+	ts4, err := NewTimeSeriesOfData("test4", start, step, []float64{math.Inf(-1), 2, 4, math.Inf(1)})
+	checkErr(t, err)
+
+	tests := []struct {
+		Series *TimeSeries
+		Width  int
+		Exp    string
+	}{
+		{ts0, 8, "▁▂▃▄▅▆▇█"},
+		{ts0, 4, "▁▃▅█"},
+		{ts1, 3, "▁ █"},
+		{ts1, 12, "▁▁▁▁    ████"},
+		{ts2, 2, "▁▁"},
+		{ts0, 0, ""},
+		{ts3, 3, "▁██"},
+		{ts4, 4, "▁▁██"},
+		{ts4, 1, " "},
+	}
+
+	for i, test := range tests {
+		got := test.Series.Sparkline(test.Width)
+		fmt.Printf("%s\n%s\n\n", test.Series, got)
+		if got != test.Exp {
+			t.Errorf("FAIL(%d): got: '%s', expected '%s'", i, got, test.Exp)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// CountValid returns the number of non NaN values.
+func (ts *TimeSeries) CountValid() int {
+	var count int
+	for _, v := range ts.data {
+		if !math.IsNaN(v) {
+			count++
+		}
+	}
+	return count
+}
+
+// CountMissing returns the number of NaN values, whatever the filler is.
+func (ts *TimeSeries) CountMissing() int {
+	return len(ts.data) - ts.CountValid()
+}
+
+// Coverage returns the ratio of non NaN values, or NaN for an empty series.
+func (ts *TimeSeries) Coverage() float64 {
+	if len(ts.data) == 0 {
+		return math.NaN()
+	}
+	return float64(ts.CountValid()) / float64(len(ts.data))
+}
+
+// Sum returns the sum of all the non NaN values, or NaN if there are none.
+func (ts *TimeSeries) Sum() float64 {
+	var sum float64
+	var count int
+	for _, v := range ts.data {
+		if math.IsNaN(v) {
+			continue
+		}
+		sum += v
+		count++
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return sum
+}
+
+// Mean returns the mean of all the non NaN values, or NaN if there are none.
+func (ts *TimeSeries) Mean() float64 {
+	return ts.mean(math.IsNaN)
+}
+
+// MeanFinite is like Mean but skips the infinities as well.
+func (ts *TimeSeries) MeanFinite() float64 {
+	return ts.mean(isNotFinite)
+}
+
+func (ts *TimeSeries) mean(skip func(float64) bool) float64 {
+	var sum float64
+	var count int
+	for _, v := range ts.data {
+		if skip(v) {
+			continue
+		}
+		sum += v
+		count++
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return sum / float64(count)
+}
+
+// TimeWeightedMean returns the mean of the series seen as a step function,
+// each non NaN value being carried forward until the next one and weighing
+// that duration, while the last one weighs a single step. Unlike Mean, a value
+// followed by a run of NaN values counts more. NaN is returned if there are no
+// non NaN values.
+func (ts *TimeSeries) TimeWeightedMean() float64 {
+	var sum float64
+	var steps int
+	last := -1
+	for i, v := range ts.data {
+		if math.IsNaN(v) {
+			continue
+		}
+		if last != -1 {
+			sum += ts.data[last] * float64(i-last)
+			steps += i - last
+		}
+		last = i
+	}
+	if last == -1 {
+		return math.NaN()
+	}
+	sum += ts.data[last]
+	steps++
+	return sum / float64(steps)
+}
+
+// Reduce folds fn over the non NaN values from the first to the last, starting
+// from initial. NaN values are skipped, so a series without valid values
+// reduces to initial.
+func (ts *TimeSeries) Reduce(initial float64, fn func(acc, v float64) float64) float64 {
+	acc := initial
+	for _, v := range ts.data {
+		if !math.IsNaN(v) {
+			acc = fn(acc, v)
+		}
+	}
+	return acc
+}
+
+// Variance returns the sample variance of the non NaN values, or NaN if there
+// are less than two of them.
+func (ts *TimeSeries) Variance() float64 {
+	return variance(ts.valid())
+}
+
+// variance returns the sample variance of vals, which must not hold NaN.
+func variance(vals []float64) float64 {
+	if len(vals) < 2 {
+		return math.NaN()
+	}
+
+	var mean float64
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+
+	var sum float64
+	for _, v := range vals {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(vals)-1)
+}
+
+// StdDev returns the sample standard deviation of the non NaN values, or NaN
+// if there are less than two of them.
+func (ts *TimeSeries) StdDev() float64 {
+	return math.Sqrt(ts.Variance())
+}
+
+// StdDevFinite is like StdDev but skips the infinities as well.
+func (ts *TimeSeries) StdDevFinite() float64 {
+	return math.Sqrt(variance(ts.values(isNotFinite)))
+}
+
+// Min returns the smallest non NaN value and the time at which it occurs.
+// If there are no such values, the zero time and NaN are returned.
+func (ts *TimeSeries) Min() (time.Time, float64) {
+	return ts.extremum(math.IsNaN, func(v, best float64) bool { return v < best })
+}
+
+// MinFinite is like Min but skips the infinities as well.
+func (ts *TimeSeries) MinFinite() (time.Time, float64) {
+	return ts.extremum(isNotFinite, func(v, best float64) bool { return v < best })
+}
+
+// Max returns the largest non NaN value and the time at which it occurs.
+// If there are no such values, the zero time and NaN are returned.
+func (ts *TimeSeries) Max() (time.Time, float64) {
+	return ts.extremum(math.IsNaN, func(v, best float64) bool { return v > best })
+}
+
+// MaxFinite is like Max but skips the infinities as well.
+func (ts *TimeSeries) MaxFinite() (time.Time, float64) {
+	return ts.extremum(isNotFinite, func(v, best float64) bool { return v > best })
+}
+
+func (ts *TimeSeries) extremum(skip func(float64) bool, better func(v, best float64) bool) (time.Time, float64) {
+	index := -1
+	best := math.NaN()
+	for i, v := range ts.data {
+		if skip(v) {
+			continue
+		}
+		if index == -1 || better(v, best) {
+			index = i
+			best = v
+		}
+	}
+	if index == -1 {
+		return time.Time{}, math.NaN()
+	}
+	return ts.TimeAt(index), best
+}
+
+// Pearson returns the Pearson correlation coefficient between both series,
+// computed over the times at which both have a non NaN value. Both series must
+// be on the same grid and overlap. The coefficient is NaN if either series is
+// constant over those times.
+func (ts *TimeSeries) Pearson(other *TimeSeries) (float64, error) {
+	xs, ys, err := ts.pairs(other)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return pearson(xs, ys)
+}
+
+// pairs returns the values of both series, aligned on their overlap, at the
+// times where both are non NaN.
+func (ts *TimeSeries) pairs(other *TimeSeries) ([]float64, []float64, error) {
+	a, b, err := Align(ts, other)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mustSameShape(a, b)
+	xs := []float64{}
+	ys := []float64{}
+	for i, x := range a.data {
+		y := b.data[i]
+		if math.IsNaN(x) || math.IsNaN(y) {
+			continue
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+	}
+	return xs, ys, nil
+}
+
+func pearson(xs, ys []float64) (float64, error) {
+	if len(xs) < 2 {
+		return math.NaN(), errors.New("less than two valid pairs of points")
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx := xs[i] - meanX
+		dy := ys[i] - meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	return cov / math.Sqrt(varX*varY), nil
+}
+
+// Quantile returns the q-th quantile of the non NaN values, interpolating
+// linearly between the closest ranks.
+func (ts *TimeSeries) Quantile(q float64) (float64, error) {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return math.NaN(), fmt.Errorf("quantile %f is not within [0, 1]", q)
+	}
+	vals := ts.valid()
+	if len(vals) == 0 {
+		return math.NaN(), errors.New("no valid values")
+	}
+	sort.Float64s(vals)
+	return quantile(vals, q), nil
+}
+
+// Median returns the 0.5 quantile of the non NaN values.
+func (ts *TimeSeries) Median() (float64, error) {
+	return ts.Quantile(0.5)
+}
+
+// valid returns a copy of the non NaN values.
+func (ts *TimeSeries) valid() []float64 {
+	return ts.values(math.IsNaN)
+}
+
+// values returns a copy of the values for which skip is false.
+func (ts *TimeSeries) values(skip func(float64) bool) []float64 {
+	vals := make([]float64, 0, len(ts.data))
+	for _, v := range ts.data {
+		if !skip(v) {
+			vals = append(vals, v)
+		}
+	}
+	return vals
+}
+
+// isNotFinite reports whether v is NaN or an infinity.
+func isNotFinite(v float64) bool {
+	return math.IsNaN(v) || math.IsInf(v, 0)
+}
+
+// quantile expects a non empty sorted slice. A rank falling on a value, or
+// between equal values, returns it as is so that infinities aren't turned into
+// NaN by the interpolation.
+func quantile(sorted []float64, q float64) float64 {
+	rank := q * float64(len(sorted)-1)
+	low := int(math.Floor(rank))
+	high := int(math.Ceil(rank))
+	frac := rank - float64(low)
+	if low == high || frac == 0 || sorted[low] == sorted[high] {
+		return sorted[low]
+	}
+	return sorted[low] + (sorted[high]-sorted[low])*frac
+}
+
+// Histogram buckets the non NaN values into bins of equal width spanning the
+// min to the max finite value. It returns the bins+1 edges and the count of
+// each bin, the last bin including the max value. Infinities are counted in
+// the first or last bin. A constant series ends up in the first bin.
+func (ts *TimeSeries) Histogram(bins int) ([]float64, []int, error) {
+	if bins < 1 {
+		return nil, nil, fmt.Errorf("bins %d must be at least 1", bins)
+	}
+	vals := ts.valid()
+	if len(vals) == 0 {
+		return nil, nil, errors.New("no valid values")
+	}
+	min, max, ok := finiteRange(vals)
+	if !ok {
+		return nil, nil, errors.New("no finite values")
+	}
+	// Dividing before subtracting avoids overflowing on ranges wider than
+	// MaxFloat64.
+	width := max/float64(bins) - min/float64(bins)
+
+	edges := make([]float64, bins+1)
+	for i := range edges {
+		edges[i] = min + float64(i)*width
+	}
+	edges[bins] = max
+
+	counts := make([]int, bins)
+	for _, v := range vals {
+		index := 0
+		switch {
+		case math.IsInf(v, 1):
+			index = bins - 1
+		case width > 0 && !math.IsInf(v, -1):
+			if f := v/width - min/width; f < float64(bins) {
+				index = int(f)
+			} else {
+				index = bins - 1
+			}
+		}
+		if index < 0 {
+			index = 0
+		}
+		counts[index]++
+	}
+	return edges, counts, nil
+}
+
+// finiteRange returns the min and max of the finite values in vals, or false
+// if there are none.
+func finiteRange(vals []float64) (float64, float64, bool) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range vals {
+		if isNotFinite(v) {
+			continue
+		}
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return min, max, min <= max
+}
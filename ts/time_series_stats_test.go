@@ -0,0 +1,310 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func checkFloat(t *testing.T, name string, got, exp float64) {
+	if got != exp && (!math.IsNaN(got) || !math.IsNaN(exp)) {
+		t.Errorf("FAIL(%s): got: '%f', expected '%f'", name, got, exp)
+	}
+}
+
+func checkTime(t *testing.T, name string, got, exp time.Time) {
+	if !got.Equal(exp) {
+		t.Errorf("FAIL(%s): got: '%s', expected '%s'", name, got, exp)
+	}
+}
+
+func TestTimeSeriesReductions(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{3, NaN, -1, 4, NaN, 2})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{NaN, NaN})
+	checkErr(t, err)
+
+	checkFloat(t, "sum", ts0.Sum(), 8)
+	checkFloat(t, "mean", ts0.Mean(), 2)
+	checkFloat(t, "time weighted mean", ts0.TimeWeightedMean(), 2.5)
+
+	minT, min := ts0.Min()
+	checkTime(t, "min time", minT, start.Add(2*step))
+	checkFloat(t, "min", min, -1)
+
+	maxT, max := ts0.Max()
+	checkTime(t, "max time", maxT, start.Add(3*step))
+	checkFloat(t, "max", max, 4)
+
+	checkFloat(t, "sum NaN", ts1.Sum(), NaN)
+	checkFloat(t, "mean NaN", ts1.Mean(), NaN)
+	checkFloat(t, "time weighted mean NaN", ts1.TimeWeightedMean(), NaN)
+
+	minT, min = ts1.Min()
+	checkTime(t, "min time NaN", minT, time.Time{})
+	checkFloat(t, "min NaN", min, NaN)
+
+	maxT, max = ts1.Max()
+	checkTime(t, "max time NaN", maxT, time.Time{})
+	checkFloat(t, "max NaN", max, NaN)
+}
+
+func TestTimeSeriesFinite(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{math.Inf(1), 1, NaN, 3, math.Inf(-1)})
+	checkErr(t, err)
+
+	checkFloat(t, "mean", ts0.Mean(), NaN)
+	checkFloat(t, "mean finite", ts0.MeanFinite(), 2)
+	checkFloat(t, "stddev", ts0.StdDev(), NaN)
+	checkFloat(t, "stddev finite", ts0.StdDevFinite(), math.Sqrt2)
+
+	_, min := ts0.Min()
+	checkFloat(t, "min", min, math.Inf(-1))
+	minT, min := ts0.MinFinite()
+	checkTime(t, "min finite time", minT, start.Add(step))
+	checkFloat(t, "min finite", min, 1)
+
+	_, max := ts0.Max()
+	checkFloat(t, "max", max, math.Inf(1))
+	maxT, max := ts0.MaxFinite()
+	checkTime(t, "max finite time", maxT, start.Add(3*step))
+	checkFloat(t, "max finite", max, 3)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{math.Inf(1), NaN})
+	checkErr(t, err)
+	checkFloat(t, "mean finite", ts1.MeanFinite(), NaN)
+	minT, min = ts1.MinFinite()
+	checkTime(t, "min finite time", minT, time.Time{})
+	checkFloat(t, "min finite", min, NaN)
+}
+
+func TestTimeSeriesPearson(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, NaN, 4, 5})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{2, 4, 6, NaN, 10})
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{5, 4, 3, 2, 1})
+	checkErr(t, err)
+
+	r, err := ts0.Pearson(ts1)
+	checkErr(t, err)
+	checkFloat(t, "pearson", r, 1)
+
+	r, err = ts0.Pearson(ts2)
+	checkErr(t, err)
+	checkFloat(t, "pearson", r, -1)
+
+	ts3, err := NewTimeSeriesOfData("test3", start.Add(4*step), step, []float64{1, 2})
+	checkErr(t, err)
+	if _, err := ts0.Pearson(ts3); err == nil {
+		t.Errorf("FAIL(pairs): expected error for a single pair")
+	}
+
+	ts4, err := NewTimeSeriesOfData("test4", start, time.Second, []float64{1, 2})
+	checkErr(t, err)
+	if _, err := ts0.Pearson(ts4); err == nil {
+		t.Errorf("FAIL(step): expected error for different steps")
+	}
+
+	ts5, err := NewTimeSeriesOfData("test5", start.Add(30*time.Second), step, []float64{1, 2, 3})
+	checkErr(t, err)
+	if _, err := ts0.Pearson(ts5); err == nil {
+		t.Errorf("FAIL(grid): expected error for series off the grid")
+	}
+
+	ts6, err := NewTimeSeriesOfData("test6", start.Add(10*step), step, []float64{1, 2, 3})
+	checkErr(t, err)
+	if _, err := ts0.Pearson(ts6); err == nil {
+		t.Errorf("FAIL(overlap): expected error for series that don't overlap")
+	}
+}
+
+func TestTimeSeriesDispersion(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{2, 4, NaN, 4, 4, 5, 5, 7, 9})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{NaN, 1, NaN})
+	checkErr(t, err)
+
+	checkFloat(t, "variance", ts0.Variance(), 32.0/7)
+	checkFloat(t, "stddev", ts0.StdDev(), math.Sqrt(32.0/7))
+	checkFloat(t, "variance single", ts1.Variance(), NaN)
+	checkFloat(t, "stddev single", ts1.StdDev(), NaN)
+}
+
+func TestTimeSeriesQuantile(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{5, 1, NaN, 4, 2, 3})
+	checkErr(t, err)
+
+	tests := []struct {
+		q   float64
+		exp float64
+	}{
+		{q: 0, exp: 1},
+		{q: 0.25, exp: 2},
+		{q: 0.5, exp: 3},
+		{q: 0.9, exp: 4.6},
+		{q: 1, exp: 5},
+	}
+
+	for _, test := range tests {
+		got, err := ts0.Quantile(test.q)
+		checkErr(t, err)
+		if math.Abs(got-test.exp) > 1e-9 {
+			t.Errorf("FAIL(quantile %f): got: '%f', expected '%f'", test.q, got, test.exp)
+		}
+	}
+
+	median, err := ts0.Median()
+	checkErr(t, err)
+	checkFloat(t, "median", median, 3)
+
+	if _, err := ts0.Quantile(1.5); err == nil {
+		t.Errorf("FAIL(quantile): expected error for q > 1")
+	}
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{1, 2, math.Inf(1)})
+	checkErr(t, err)
+	max, err := ts2.Quantile(1)
+	checkErr(t, err)
+	checkFloat(t, "quantile Inf", max, math.Inf(1))
+	high, err := ts2.Quantile(0.75)
+	checkErr(t, err)
+	checkFloat(t, "quantile Inf", high, math.Inf(1))
+
+	ts3, err := NewTimeSeriesOfData("test3", start, step, []float64{1, math.Inf(1), math.Inf(1), math.Inf(1)})
+	checkErr(t, err)
+	median, err = ts3.Median()
+	checkErr(t, err)
+	checkFloat(t, "median Inf", median, math.Inf(1))
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{NaN})
+	checkErr(t, err)
+	if _, err := ts1.Median(); err == nil {
+		t.Errorf("FAIL(quantile): expected error without valid values")
+	}
+}
+
+func TestTimeSeriesCoverage(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 0, NaN})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 3, 0)
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{})
+	checkErr(t, err)
+
+	if got := ts0.CountValid(); got != 2 {
+		t.Errorf("FAIL(valid): got: '%d', expected '%d'", got, 2)
+	}
+	if got := ts0.CountMissing(); got != 2 {
+		t.Errorf("FAIL(missing): got: '%d', expected '%d'", got, 2)
+	}
+	checkFloat(t, "coverage", ts0.Coverage(), 0.5)
+
+	if got := ts1.CountMissing(); got != 0 {
+		t.Errorf("FAIL(missing): got: '%d', expected '%d'", got, 0)
+	}
+	checkFloat(t, "coverage", ts1.Coverage(), 1)
+	checkFloat(t, "coverage empty", ts2.Coverage(), NaN)
+}
+
+func TestTimeSeriesHistogram(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 1, NaN, 2, 3, 4, 4})
+	checkErr(t, err)
+
+	edges, counts, err := ts0.Histogram(4)
+	checkErr(t, err)
+	checkData(t, edges, []float64{0, 1, 2, 3, 4})
+	if fmt.Sprint(counts) != fmt.Sprint([]int{1, 1, 1, 3}) {
+		t.Errorf("FAIL(counts): got: '%v', expected '%v'", counts, []int{1, 1, 1, 3})
+	}
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 3, 5)
+	checkErr(t, err)
+	edges, counts, err = ts1.Histogram(2)
+	checkErr(t, err)
+	checkData(t, edges, []float64{5, 5, 5})
+	if fmt.Sprint(counts) != fmt.Sprint([]int{3, 0}) {
+		t.Errorf("FAIL(counts): got: '%v', expected '%v'", counts, []int{3, 0})
+	}
+
+	ts3, err := NewTimeSeriesOfData("test3", start, step, []float64{0, 1, math.Inf(1), math.Inf(-1), 2})
+	checkErr(t, err)
+	edges, counts, err = ts3.Histogram(2)
+	checkErr(t, err)
+	checkData(t, edges, []float64{0, 1, 2})
+	if fmt.Sprint(counts) != fmt.Sprint([]int{2, 3}) {
+		t.Errorf("FAIL(counts): got: '%v', expected '%v'", counts, []int{2, 3})
+	}
+
+	ts5, err := NewTimeSeriesOfData("test5", start, step, []float64{-math.MaxFloat64, math.MaxFloat64, 0})
+	checkErr(t, err)
+	edges, counts, err = ts5.Histogram(2)
+	checkErr(t, err)
+	checkData(t, edges, []float64{-math.MaxFloat64, 0, math.MaxFloat64})
+	if fmt.Sprint(counts) != fmt.Sprint([]int{1, 2}) {
+		t.Errorf("FAIL(counts): got: '%v', expected '%v'", counts, []int{1, 2})
+	}
+
+	ts4, err := NewTimeSeriesOfData("test4", start, step, []float64{math.Inf(1), NaN})
+	checkErr(t, err)
+	if _, _, err := ts4.Histogram(2); err == nil {
+		t.Errorf("FAIL(finite): expected error without finite values")
+	}
+
+	if _, _, err := ts0.Histogram(0); err == nil {
+		t.Errorf("FAIL(bins): expected error for 0 bins")
+	}
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{NaN})
+	checkErr(t, err)
+	if _, _, err := ts2.Histogram(3); err == nil {
+		t.Errorf("FAIL(valid): expected error without valid values")
+	}
+}
+
+func TestTimeSeriesReduce(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{2, NaN, 3, 4})
+	checkErr(t, err)
+
+	product := ts0.Reduce(1, func(acc, v float64) float64 { return acc * v })
+	checkFloat(t, "product", product, 24)
+
+	order := ts0.Reduce(0, func(acc, v float64) float64 { return acc*10 + v })
+	checkFloat(t, "order", order, 234)
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 2, NaN)
+	checkErr(t, err)
+	checkFloat(t, "empty", ts1.Reduce(7, math.Max), 7)
+}
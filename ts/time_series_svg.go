@@ -0,0 +1,216 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ChartOptions controls how a series is rendered. Zero values are replaced by
+// a 640x240 viewport with a black stroke.
+type ChartOptions struct {
+	Width  int
+	Height int
+	Stroke string
+	Axes   bool
+}
+
+const chartMargin = 20
+
+// chartPalette is cycled through to color each series of a multi series chart.
+var chartPalette = []string{
+	"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+	"#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+}
+
+func (opts ChartOptions) withDefaults() ChartOptions {
+	if opts.Width <= 0 {
+		opts.Width = 640
+	}
+	if opts.Height <= 0 {
+		opts.Height = 240
+	}
+	if opts.Stroke == "" {
+		opts.Stroke = "black"
+	}
+	return opts
+}
+
+// chart maps the times and values of series onto the plot area of a viewport,
+// the x axis spanning the union of their ranges and the y axis the min to the
+// max of their finite values.
+type chart struct {
+	opts     ChartOptions
+	start    time.Time
+	last     time.Time
+	min, max float64
+
+	left, right, top, bottom float64
+}
+
+type point struct {
+	x, y float64
+}
+
+func newChart(opts ChartOptions, series ...*TimeSeries) *chart {
+	c := &chart{
+		opts: opts,
+		min:  math.Inf(1),
+		max:  math.Inf(-1),
+
+		right:  float64(opts.Width),
+		bottom: float64(opts.Height),
+	}
+	if opts.Axes {
+		c.left = chartMargin
+		c.bottom -= chartMargin
+	}
+
+	for i, ts := range series {
+		last := ts.End().Add(-ts.step)
+		if i == 0 || ts.start.Before(c.start) {
+			c.start = ts.start
+		}
+		if i == 0 || last.After(c.last) {
+			c.last = last
+		}
+		if min, max, ok := finiteRange(ts.data); ok {
+			c.min = math.Min(c.min, min)
+			c.max = math.Max(c.max, max)
+		}
+	}
+	return c
+}
+
+func (c *chart) x(t time.Time) float64 {
+	span := c.last.Sub(c.start)
+	if span <= 0 {
+		return (c.left + c.right) / 2
+	}
+	return c.left + float64(t.Sub(c.start))/float64(span)*(c.right-c.left)
+}
+
+func (c *chart) y(v float64) float64 {
+	if c.max <= c.min {
+		return (c.top + c.bottom) / 2
+	}
+	return c.bottom - (v-c.min)/(c.max-c.min)*(c.bottom-c.top)
+}
+
+// segments returns the runs of finite points of ts, NaN values and infinities
+// breaking the line so that holes aren't connected.
+func (c *chart) segments(ts *TimeSeries) [][]point {
+	segments := [][]point{}
+	var segment []point
+	ts.ForEach(func(i int, t time.Time, v float64) bool {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			if len(segment) > 0 {
+				segments = append(segments, segment)
+			}
+			segment = nil
+			return true
+		}
+		segment = append(segment, point{c.x(t), c.y(v)})
+		return true
+	})
+	if len(segment) > 0 {
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func (c *chart) writeHeader(s *bytes.Buffer) {
+	fmt.Fprintf(s, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		c.opts.Width, c.opts.Height, c.opts.Width, c.opts.Height)
+	if !c.opts.Axes {
+		return
+	}
+	fmt.Fprintf(s, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\" stroke=\"black\"/>\n",
+		formatCoord(c.left), formatCoord(c.top), formatCoord(c.left), formatCoord(c.bottom))
+	fmt.Fprintf(s, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\" stroke=\"black\"/>\n",
+		formatCoord(c.left), formatCoord(c.bottom), formatCoord(c.right), formatCoord(c.bottom))
+}
+
+func (c *chart) writeSeries(s *bytes.Buffer, ts *TimeSeries, stroke string) {
+	stroke = html.EscapeString(stroke)
+	for _, segment := range c.segments(ts) {
+		if len(segment) == 1 {
+			fmt.Fprintf(s, "<circle cx=\"%s\" cy=\"%s\" r=\"1\" fill=\"%s\"/>\n",
+				formatCoord(segment[0].x), formatCoord(segment[0].y), stroke)
+			continue
+		}
+		s.WriteString("<polyline fill=\"none\" stroke=\"")
+		s.WriteString(stroke)
+		s.WriteString("\" points=\"")
+		for i, p := range segment {
+			if i > 0 {
+				s.WriteByte(' ')
+			}
+			s.WriteString(formatCoord(p.x))
+			s.WriteByte(',')
+			s.WriteString(formatCoord(p.y))
+		}
+		s.WriteString("\"/>\n")
+	}
+}
+
+// RenderSVG writes the series as a line chart in an SVG document, scaled on
+// the finite values. NaN values and infinities leave gaps in the line.
+func (ts *TimeSeries) RenderSVG(w io.Writer, opts ChartOptions) error {
+	opts = opts.withDefaults()
+	c := newChart(opts, ts)
+
+	s := bytes.NewBufferString("")
+	c.writeHeader(s)
+	c.writeSeries(s, ts, opts.Stroke)
+	s.WriteString("</svg>\n")
+
+	_, err := s.WriteTo(w)
+	return err
+}
+
+// RenderSVGMulti writes all the series in a single SVG line chart sharing the
+// same axes, each series colored from a palette and named by its key in a
+// legend. The stroke of opts is not used.
+func RenderSVGMulti(w io.Writer, opts ChartOptions, series ...*TimeSeries) error {
+	if len(series) == 0 {
+		return errors.New("no series to render")
+	}
+	opts = opts.withDefaults()
+	c := newChart(opts, series...)
+
+	s := bytes.NewBufferString("")
+	c.writeHeader(s)
+	for i, ts := range series {
+		c.writeSeries(s, ts, chartPalette[i%len(chartPalette)])
+	}
+	c.writeLegend(s, series)
+	s.WriteString("</svg>\n")
+
+	_, err := s.WriteTo(w)
+	return err
+}
+
+func (c *chart) writeLegend(s *bytes.Buffer, series []*TimeSeries) {
+	const lineHeight = 14
+	x := c.left + 10
+	for i, ts := range series {
+		y := c.top + float64((i+1)*lineHeight)
+		color := chartPalette[i%len(chartPalette)]
+		fmt.Fprintf(s, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\" stroke=\"%s\"/>\n",
+			formatCoord(x), formatCoord(y-4), formatCoord(x+10), formatCoord(y-4), color)
+		fmt.Fprintf(s, "<text x=\"%s\" y=\"%s\" font-size=\"10\">%s</text>\n",
+			formatCoord(x+14), formatCoord(y), html.EscapeString(ts.Key()))
+	}
+}
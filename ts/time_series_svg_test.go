@@ -0,0 +1,98 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesRenderSVG(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 1, 2})
+	checkErr(t, err)
+
+	var buf bytes.Buffer
+	checkErr(t, ts0.RenderSVG(&buf, ChartOptions{Width: 100, Height: 50, Stroke: "red"}))
+	fmt.Printf("%s\n%s\n", ts0, buf.String())
+
+	exp := "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"100\" height=\"50\" viewBox=\"0 0 100 50\">\n" +
+		"<polyline fill=\"none\" stroke=\"red\" points=\"0.00,50.00 50.00,25.00 100.00,0.00\"/>\n" +
+		"</svg>\n"
+	if buf.String() != exp {
+		t.Errorf("FAIL(svg): got:\n%s\nexpected:\n%s", buf.String(), exp)
+	}
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{1, 2, NaN, 3, 4, NaN, 5})
+	checkErr(t, err)
+
+	buf.Reset()
+	checkErr(t, ts1.RenderSVG(&buf, ChartOptions{Axes: true}))
+	fmt.Printf("%s\n%s\n", ts1, buf.String())
+
+	got := buf.String()
+	if n := strings.Count(got, "<polyline"); n != 2 {
+		t.Errorf("FAIL(segments): got: '%d' polylines, expected '%d'", n, 2)
+	}
+	if n := strings.Count(got, "<circle"); n != 1 {
+		t.Errorf("FAIL(segments): got: '%d' circles, expected '%d'", n, 1)
+	}
+	if n := strings.Count(got, "<line"); n != 2 {
+		t.Errorf("FAIL(axes): got: '%d' lines, expected '%d'", n, 2)
+	}
+	if !strings.Contains(got, "width=\"640\" height=\"240\"") {
+		t.Errorf("FAIL(defaults): missing default viewport in:\n%s", got)
+	}
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{0, 1, math.Inf(1), 2})
+	checkErr(t, err)
+
+	buf.Reset()
+	checkErr(t, ts2.RenderSVG(&buf, ChartOptions{Width: 90, Height: 50}))
+	fmt.Printf("%s\n%s\n", ts2, buf.String())
+
+	exp = "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"90\" height=\"50\" viewBox=\"0 0 90 50\">\n" +
+		"<polyline fill=\"none\" stroke=\"black\" points=\"0.00,50.00 30.00,25.00\"/>\n" +
+		"<circle cx=\"90.00\" cy=\"0.00\" r=\"1\" fill=\"black\"/>\n" +
+		"</svg>\n"
+	if buf.String() != exp {
+		t.Errorf("FAIL(svg): got:\n%s\nexpected:\n%s", buf.String(), exp)
+	}
+}
+
+func TestRenderSVGMulti(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{0, 10})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("a<b", start.Add(step), step, []float64{5, 20})
+	checkErr(t, err)
+
+	var buf bytes.Buffer
+	checkErr(t, RenderSVGMulti(&buf, ChartOptions{Width: 100, Height: 40}, ts0, ts1))
+	got := buf.String()
+	fmt.Printf("%s\n%s\n%s\n", ts0, ts1, got)
+
+	for _, exp := range []string{
+		"<polyline fill=\"none\" stroke=\"#1f77b4\" points=\"0.00,40.00 50.00,20.00\"/>",
+		"<polyline fill=\"none\" stroke=\"#ff7f0e\" points=\"50.00,30.00 100.00,0.00\"/>",
+		">test0</text>",
+		">a&lt;b</text>",
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("FAIL(svg): missing '%s' in:\n%s", exp, got)
+		}
+	}
+
+	if err := RenderSVGMulti(&buf, ChartOptions{}); err == nil {
+		t.Errorf("FAIL(series): expected error without series")
+	}
+}
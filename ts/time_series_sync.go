@@ -0,0 +1,60 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"sync"
+	"time"
+)
+
+// SyncTimeSeries wraps a TimeSeries so that it can be read and written from
+// multiple goroutines. Reads take the read lock and mutations the write lock.
+//
+// Iterators don't hold any lock, use Snapshot to iterate over a consistent
+// copy of the series instead.
+type SyncTimeSeries struct {
+	sync.RWMutex
+	series *TimeSeries
+}
+
+// NewSyncTimeSeries wraps ts, which should not be used directly afterwards.
+func NewSyncTimeSeries(ts *TimeSeries) *SyncTimeSeries {
+	return &SyncTimeSeries{series: ts}
+}
+
+func (sts *SyncTimeSeries) GetAt(t time.Time) (float64, bool) {
+	sts.RLock()
+	defer sts.RUnlock()
+	return sts.series.GetAt(t)
+}
+
+func (sts *SyncTimeSeries) SetAt(t time.Time, value float64) bool {
+	sts.Lock()
+	defer sts.Unlock()
+	return sts.series.SetAt(t, value)
+}
+
+func (sts *SyncTimeSeries) ExtendWith(data ...float64) {
+	sts.Lock()
+	defer sts.Unlock()
+	sts.series.ExtendWith(data...)
+}
+
+func (sts *SyncTimeSeries) ExtendTo(t time.Time) {
+	sts.Lock()
+	defer sts.Unlock()
+	sts.series.ExtendTo(t)
+}
+
+func (sts *SyncTimeSeries) Data() []float64 {
+	sts.RLock()
+	defer sts.RUnlock()
+	return sts.series.Data()
+}
+
+// Snapshot returns a copy of the wrapped series taken under the read lock.
+func (sts *SyncTimeSeries) Snapshot() *TimeSeries {
+	sts.RLock()
+	defer sts.RUnlock()
+	return sts.series.Copy()
+}
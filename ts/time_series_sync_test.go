@@ -0,0 +1,45 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncTimeSeries(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfLength("test0", start, step, 1, 0)
+	checkErr(t, err)
+	sts := NewSyncTimeSeries(ts0)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i < 100; i++ {
+			sts.ExtendWith(float64(i))
+			sts.SetAt(start, float64(i))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			sts.GetAt(start)
+			sts.Data()
+			it := sts.Snapshot().Iterator()
+			for _, ok := it.Next(); ok; _, ok = it.Next() {
+			}
+		}
+	}()
+	wg.Wait()
+
+	snapshot := sts.Snapshot()
+	checkLengthDataEqual(t, snapshot.data, 100)
+	if v, _ := sts.GetAt(start); v != 99 {
+		t.Errorf("FAIL(value): got: '%f', expected '%f'", v, 99.0)
+	}
+}
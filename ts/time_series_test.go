@@ -192,7 +192,20 @@ func TestTimeSeriesExtend(t *testing.T) {
 	ts2.ExtendTo(time.Date(2016, time.Month(1), 25, 10, 5, 0, 0, time.UTC))
 
 	ts3 := ts0.Copy()
-	ts3.ExtendWith([]float64{4, 5, 6})
+	ts3.ExtendWith([]float64{4, 5, 6}...)
+
+	ts4 := ts0.Copy()
+	ts4.ExtendTo(time.Date(2016, time.Month(1), 25, 10, 4, 30, 0, time.UTC))
+
+	ts5 := ts0.Copy()
+	ts5.ExtendTo(time.Date(2016, time.Month(1), 25, 10, 1, 30, 0, time.UTC))
+
+	ts6 := ts0.Copy()
+	ts6.ExtendTo(ts0.End())
+
+	if _, ok := ts4.GetAt(time.Date(2016, time.Month(1), 25, 10, 4, 30, 0, time.UTC)); !ok {
+		t.Errorf("FAIL(ExtendTo): time extended to should be within the series")
+	}
 
 	tss := []struct {
 		Got *TimeSeries
@@ -225,6 +238,33 @@ func TestTimeSeriesExtend(t *testing.T) {
 				data:  []float64{1, 2, 3, 4, 5, 6},
 			},
 		},
+		{
+			Got: ts4,
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start,
+				step:  step,
+				data:  []float64{1, 2, 3, NaN, NaN},
+			},
+		},
+		{
+			Got: ts5,
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start,
+				step:  step,
+				data:  []float64{1, 2, 3},
+			},
+		},
+		{
+			Got: ts6,
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start,
+				step:  step,
+				data:  []float64{1, 2, 3, NaN},
+			},
+		},
 	}
 
 	for _, pair := range tss {
@@ -288,3 +328,581 @@ func TestTimeSeriesIterator(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeSeriesGetAtNearest(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3})
+	checkErr(t, err)
+
+	tests := []struct {
+		t       time.Time
+		nearest time.Time
+		val     float64
+		ok      bool
+	}{
+		{t: start, nearest: start, val: 1, ok: true},
+		{t: start.Add(20 * time.Second), nearest: start, val: 1, ok: true},
+		{t: start.Add(40 * time.Second), nearest: start.Add(step), val: 2, ok: true},
+		{t: start.Add(-20 * time.Second), nearest: start, val: 1, ok: true},
+		{t: start.Add(-40 * time.Second), nearest: start.Add(-step), val: NaN, ok: false},
+		{t: start.Add(150 * time.Second), nearest: start.Add(3 * step), val: NaN, ok: false},
+	}
+
+	for i, test := range tests {
+		nearest, val, ok := ts0.GetAtNearest(test.t)
+		if !nearest.Equal(test.nearest) || ok != test.ok ||
+			(val != test.val && !(math.IsNaN(val) && math.IsNaN(test.val))) {
+			t.Errorf("FAIL(%d): got: '%s', '%f', '%v', expected '%s', '%f', '%v'",
+				i, nearest, val, ok, test.nearest, test.val, test.ok)
+		}
+	}
+
+	if v, _ := ts0.GetAt(start.Add(40 * time.Second)); v != 1 {
+		t.Errorf("FAIL(GetAt): should still floor to the earlier grid point")
+	}
+}
+
+func TestTimeSeriesApply(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 3})
+	checkErr(t, err)
+
+	got := ts0.Apply("scale", func(v float64) float64 { return v * 1000 })
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "scale(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{1000, NaN, 3000},
+	})
+
+	got = ts0.Apply("zero", func(v float64) float64 {
+		if math.IsNaN(v) {
+			return 0
+		}
+		return v
+	})
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "zero(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{1, 0, 3},
+	})
+	checkData(t, ts0.data, []float64{1, NaN, 3})
+}
+
+func TestTimeSeriesForEach(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 3, 4})
+	checkErr(t, err)
+
+	got := []float64{}
+	ts0.ForEach(func(i int, ti time.Time, v float64) bool {
+		if !ti.Equal(start.Add(time.Duration(i) * step)) {
+			t.Errorf("FAIL(time): got: '%s' at index '%d'", ti, i)
+		}
+		got = append(got, v)
+		return i < 2
+	})
+	checkData(t, got, []float64{1, NaN, 3})
+}
+
+func benchmarkSeries(b *testing.B) *TimeSeries {
+	ts, err := NewTimeSeriesOfLength("bench", time.Now(), time.Second, 10000, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return ts
+}
+
+func BenchmarkTimeSeriesForEach(b *testing.B) {
+	ts := benchmarkSeries(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		var sum float64
+		ts.ForEach(func(i int, t time.Time, v float64) bool {
+			sum += v
+			return true
+		})
+	}
+}
+
+func BenchmarkTimeSeriesData(b *testing.B) {
+	ts := benchmarkSeries(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		var sum float64
+		for _, v := range ts.Data() {
+			sum += v
+		}
+	}
+}
+
+func TestTimeSeriesVerify(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2})
+	checkErr(t, err)
+	checkErr(t, ts0.Verify())
+
+	ts0.ExtendWith(3, 4)
+	checkErr(t, ts0.Verify())
+
+	tests := []*TimeSeries{
+		{key: "step", start: start, data: []float64{}},
+		{key: "negative", start: start, step: -step, data: []float64{}},
+		{key: "start", step: step, data: []float64{}},
+		{key: "data", start: start, step: step},
+		{key: "overflow", start: start, step: time.Duration(1) << 62, data: make([]float64, 4)},
+	}
+
+	for _, test := range tests {
+		if err := test.Verify(); err == nil {
+			t.Errorf("FAIL(%s): expected error", test.key)
+		}
+	}
+}
+
+func TestTimeSeriesIteratorSeek(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3})
+	checkErr(t, err)
+
+	it := ts0.Iterator()
+	if v, ok := it.Peek(); !ok || v != 1 {
+		t.Errorf("FAIL(Peek): got: '%f', '%v', expected '%f'", v, ok, 1.0)
+	}
+	it.Next()
+	if v, ok := it.Peek(); !ok || v != 2 {
+		t.Errorf("FAIL(Peek): got: '%f', '%v', expected '%f'", v, ok, 2.0)
+	}
+
+	if !it.Seek(start.Add(150 * time.Second)) {
+		t.Errorf("FAIL(Seek): should be within the series")
+	}
+	if v, ok := it.Next(); !ok || v != 3 {
+		t.Errorf("FAIL(Seek): got: '%f', '%v', expected '%f'", v, ok, 3.0)
+	}
+	if _, ok := it.Peek(); ok {
+		t.Errorf("FAIL(Peek): should be exhausted")
+	}
+	if it.Seek(start.Add(time.Hour)) {
+		t.Errorf("FAIL(Seek): should be outside the series")
+	}
+
+	it.Reset()
+	if v, ok := it.Next(); !ok || v != 1 {
+		t.Errorf("FAIL(Reset): got: '%f', '%v', expected '%f'", v, ok, 1.0)
+	}
+}
+
+func TestRingTimeSeries(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0 := NewRingTimeSeries("ring", start, step, 3)
+	ts0.Push(1)
+	ts0.Push(2)
+	checkTimeSeries(t, ts0, &TimeSeries{
+		key:   "ring",
+		start: start,
+		step:  step,
+		data:  []float64{1, 2},
+	})
+
+	ts0.ExtendWith(3, 4, 5)
+	checkTimeSeries(t, ts0, &TimeSeries{
+		key:   "ring",
+		start: start.Add(2 * step),
+		step:  step,
+		data:  []float64{3, 4, 5},
+	})
+	if v, ok := ts0.GetAt(start.Add(3 * step)); !ok || v != 4 {
+		t.Errorf("FAIL(GetAt): got: '%f', '%v', expected '%f'", v, ok, 4.0)
+	}
+	if _, ok := ts0.GetAt(start.Add(step)); ok {
+		t.Errorf("FAIL(GetAt): dropped point should be outside the series")
+	}
+
+	ts0.ExtendTo(start.Add(6 * step))
+	checkTimeSeries(t, ts0, &TimeSeries{
+		key:   "ring",
+		start: start.Add(4 * step),
+		step:  step,
+		data:  []float64{5, NaN, NaN},
+	})
+	checkErr(t, ts0.Verify())
+}
+
+func TestRingTimeSeriesPanics(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+
+	for _, capacity := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("FAIL(capacity): expected panic for capacity %d", capacity)
+				}
+			}()
+			NewRingTimeSeries("ring", start, time.Minute, capacity)
+		}()
+	}
+}
+
+func TestTimeSeriesFiller(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfTimeRange("test0", start, start.Add(2*step), step, NaN)
+	checkErr(t, err)
+	checkFloat(t, "filler", ts0.Filler(), NaN)
+
+	ts0.SetFiller(0, false)
+	checkFloat(t, "filler", ts0.Filler(), 0)
+	ts0.ExtendBy(step)
+	checkData(t, ts0.data, []float64{NaN, NaN, 0})
+
+	ts0.SetAt(start, 7)
+	ts0.SetFiller(-1, true)
+	checkData(t, ts0.data, []float64{7, NaN, -1})
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{1, NaN, 3})
+	checkErr(t, err)
+	ts1.SetFiller(0, true)
+	checkData(t, ts1.data, []float64{1, 0, 3})
+	checkFloat(t, "filler", ts1.Copy().Filler(), 0)
+}
+
+func TestTimeSeriesEqual(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, math.Inf(1)})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test0", start, step, []float64{1.001, NaN, math.Inf(1)})
+	checkErr(t, err)
+
+	tests := []struct {
+		Other       *TimeSeries
+		Eps         float64
+		Equal       bool
+		EqualApprox bool
+	}{
+		{ts0.Copy(), 0.01, true, true},
+		{ts1, 0.01, false, true},
+		{ts1, 0.0001, false, false},
+		{ts0.Rebase(start.Add(step)), 0.01, false, false},
+		{ts0.Scale(1), 0.01, false, false},
+		{ts0.TrimTrailing(), 0.01, true, true},
+		{ts0.Trim().Reverse(), 10, false, false},
+	}
+
+	for i, test := range tests {
+		if got := ts0.Equal(test.Other); got != test.Equal {
+			t.Errorf("FAIL(%d): Equal got: '%v', expected '%v'", i, got, test.Equal)
+		}
+		if got := ts0.EqualApprox(test.Other, test.Eps); got != test.EqualApprox {
+			t.Errorf("FAIL(%d): EqualApprox got: '%v', expected '%v'", i, got, test.EqualApprox)
+		}
+	}
+}
+
+func TestTimeSeriesFormat(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2.125, NaN})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 25, 1)
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{})
+	checkErr(t, err)
+
+	tests := []struct {
+		Got string
+		Exp string
+	}{
+		{
+			ts0.String(),
+			"test0 Start: 2016-01-25 10:00:00 +0000 UTC End: 2016-01-25 10:03:00 +0000 UTC Step: 1m0s Length: 3 1.00,2.12,NaN",
+		},
+		{
+			ts0.Format(FormatOptions{Precision: -1}),
+			"1,2.125,NaN",
+		},
+		{
+			ts0.Format(FormatOptions{Precision: 1, MaxPoints: 2}),
+			"1.0,2.1,...",
+		},
+		{
+			ts1.Format(FormatOptions{Precision: 0, MaxPoints: 20}),
+			"1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,...",
+		},
+		{
+			ts2.String(),
+			"test2 Start: 2016-01-25 10:00:00 +0000 UTC End: 2016-01-25 10:00:00 +0000 UTC Step: 1m0s Length: 0",
+		},
+	}
+
+	for i, test := range tests {
+		if test.Got != test.Exp {
+			t.Errorf("FAIL(%d): got: '%s', expected '%s'", i, test.Got, test.Exp)
+		}
+	}
+}
+
+func TestNewTimeSeriesFromSamples(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	times := []time.Time{
+		start.Add(2 * time.Second),
+		start.Add(58 * time.Second),
+		start.Add(62 * time.Second),
+		start.Add(3*step + 29*time.Second),
+		start.Add(-20 * time.Second),
+	}
+	values := []float64{1, 2, 3, 4, 5}
+
+	got, err := NewTimeSeriesFromSamples("test0", start, step, times, values)
+	checkErr(t, err)
+	exp := &TimeSeries{
+		key:   "test0",
+		start: start,
+		step:  step,
+		data:  []float64{5, 3, NaN, 4},
+	}
+	fmt.Printf("%s\n%s\n\n", got, exp)
+	checkTimeSeries(t, got, exp)
+	checkFloat(t, "filler", got.Filler(), NaN)
+
+	if _, err := NewTimeSeriesFromSamples("test1", start, step, times, values[:2]); err == nil {
+		t.Errorf("FAIL(length): expected error for mismatched lengths")
+	}
+	if _, err := NewTimeSeriesFromSamples("test2", start, step, []time.Time{start.Add(-time.Hour)}, []float64{1}); err == nil {
+		t.Errorf("FAIL(start): expected error for a sample before start")
+	}
+}
+
+func TestTimeSeriesValidIterator(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{NaN, 1, NaN, NaN, 4, NaN})
+	checkErr(t, err)
+
+	times := []time.Time{}
+	got := []float64{}
+	it := ts0.ValidIterator()
+	for ti, val, ok := it.Next(); ok; ti, val, ok = it.Next() {
+		times = append(times, ti)
+		got = append(got, val)
+	}
+	checkData(t, got, []float64{1, 4})
+	if len(times) == 2 {
+		checkTime(t, "first", times[0], start.Add(step))
+		checkTime(t, "second", times[1], start.Add(4*step))
+	}
+	if _, _, ok := it.Next(); ok {
+		t.Errorf("FAIL(Next): expected exhausted iterator")
+	}
+
+	ts1, err := NewTimeSeriesOfLength("test1", start, step, 3, NaN)
+	checkErr(t, err)
+	if _, _, ok := ts1.ValidIterator().Next(); ok {
+		t.Errorf("FAIL(Next): expected no valid value")
+	}
+}
+
+func TestTimeSeriesInvalidStep(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+
+	for _, step := range []time.Duration{0, -time.Minute, -1} {
+		if _, err := NewTimeSeries("test", start, start.Add(time.Hour), step, 1); err == nil {
+			t.Errorf("FAIL(step): expected error for step '%v'", step)
+		}
+		if _, err := NewTimeSeriesOfData("test", start, step, []float64{1, 2}); err == nil {
+			t.Errorf("FAIL(step): expected error for step '%v'", step)
+		}
+	}
+
+	step := time.Duration(1 << 33)
+	ts0, err := NewTimeSeriesOfLength("test", start, step, 2, 1)
+	checkErr(t, err)
+	checkData(t, ts0.data, []float64{1, 1})
+}
+
+func TestTimeSeriesOverflow(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2500, time.Month(1), 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := NewTimeSeriesOfTimeRange("test", start, end, time.Hour, 0); err == nil {
+		t.Errorf("FAIL(range): expected error for a range that overflows a duration")
+	}
+	if _, err := NewTimeSeriesOfLength("test", start, time.Hour, math.MaxInt64/2, 0); err == nil {
+		t.Errorf("FAIL(length): expected error for a length that overflows a duration")
+	}
+	if _, err := NewTimeSeriesOfLength("test", start, time.Hour, -1, 0); err == nil {
+		t.Errorf("FAIL(length): expected error for a negative length")
+	}
+}
+
+func TestTimeSeriesLen(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{})
+	checkErr(t, err)
+	if ts0.Len() != 0 || !ts0.IsEmpty() {
+		t.Errorf("FAIL(len): got: '%d', '%v', expected '%d', '%v'", ts0.Len(), ts0.IsEmpty(), 0, true)
+	}
+
+	ts0.ExtendWith(1, NaN)
+	if ts0.Len() != 2 || ts0.IsEmpty() {
+		t.Errorf("FAIL(len): got: '%d', '%v', expected '%d', '%v'", ts0.Len(), ts0.IsEmpty(), 2, false)
+	}
+}
+
+func TestTimeSeriesWithKey(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2})
+	checkErr(t, err)
+
+	got := ts0.Scale(2).WithKey("double")
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "double",
+		start: start,
+		step:  step,
+		data:  []float64{2, 4},
+	})
+	checkKey(t, ts0.WithKey("other").Key(), "other")
+	checkKey(t, ts0.Key(), "test0")
+}
+
+func TestTimeSeriesGetAtOr(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN})
+	checkErr(t, err)
+	ts0.SetFiller(-1, false)
+
+	checkFloat(t, "in range", ts0.GetAtOr(start, 0), 1)
+	checkFloat(t, "NaN", ts0.GetAtOr(start.Add(step), 0), 0)
+	checkFloat(t, "before", ts0.GetAtOr(start.Add(-step), 0), 0)
+	checkFloat(t, "after", ts0.GetAtOr(start.Add(time.Hour), 5), 5)
+
+	checkFloat(t, "filler in range", ts0.GetAtOrFiller(start), 1)
+	checkFloat(t, "filler NaN", ts0.GetAtOrFiller(start.Add(step)), -1)
+	checkFloat(t, "filler after", ts0.GetAtOrFiller(start.Add(time.Hour)), -1)
+}
+
+func TestNewTimeSeriesFromTimedData(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 30, 0, time.UTC)
+	step := time.Minute
+
+	times := []time.Time{
+		start.Add(4 * step),
+		start,
+		start.Add(step),
+		start.Add(5 * step),
+	}
+	got, err := NewTimeSeriesFromTimedData("test0", step, times, []float64{4, 0, 1, 5})
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "test0",
+		start: start,
+		step:  step,
+		data:  []float64{0, 1, NaN, NaN, 4, 5},
+	})
+
+	if _, err := NewTimeSeriesFromTimedData("test1", step, nil, nil); err == nil {
+		t.Errorf("FAIL(times): expected error without times")
+	}
+	if _, err := NewTimeSeriesFromTimedData("test2", step, times, []float64{1}); err == nil {
+		t.Errorf("FAIL(length): expected error for mismatched lengths")
+	}
+}
+
+func TestTimeSeriesCompact(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 3})
+	checkErr(t, err)
+	ts0.SetFiller(NaN, false)
+	ts0.ExtendTo(start.Add(100 * step))
+	ts0.Compact()
+	checkTimeSeries(t, ts0, &TimeSeries{
+		key:   "test0",
+		start: start,
+		step:  step,
+		data:  []float64{1, NaN, 3},
+	})
+	if cap(ts0.data) != 3 {
+		t.Errorf("FAIL(capacity): got: '%d', expected '%d'", cap(ts0.data), 3)
+	}
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{0, 0})
+	checkErr(t, err)
+	ts1.SetFiller(0, false)
+	ts1.Compact()
+	checkErr(t, ts1.Verify())
+	checkLengthDataEqual(t, ts1.data, 0)
+}
+
+func TestTimeSeriesTimes(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3})
+	checkErr(t, err)
+
+	times := ts0.Times()
+	if len(times) != 3 {
+		t.Fatalf("FAIL(times length): got: '%d', expected '%d'", len(times), 3)
+	}
+	for i, tm := range times {
+		checkTime(t, "times", tm, start.Add(time.Duration(i)*step))
+		checkTime(t, "time at", ts0.TimeAt(i), tm)
+	}
+	checkTime(t, "time at end", ts0.TimeAt(3), ts0.End())
+	checkTime(t, "time before start", ts0.TimeAt(-1), start.Add(-step))
+}
+
+func TestMustTimeSeriesOfData(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0 := MustTimeSeriesOfData("test0", start, step, []float64{1, NaN, 3})
+	checkTimeSeries(t, ts0, &TimeSeries{
+		key:   "test0",
+		start: start,
+		step:  step,
+		data:  []float64{1, NaN, 3},
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("FAIL(step): expected panic for a zero step")
+		}
+	}()
+	MustTimeSeriesOfData("test1", start, 0, []float64{1})
+}
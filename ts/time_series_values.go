@@ -0,0 +1,173 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Clamp returns a copy where each non NaN value is bounded to [min, max]. The
+// bounds are swapped if min is larger than max.
+func (ts *TimeSeries) Clamp(min, max float64) *TimeSeries {
+	if min > max {
+		min, max = max, min
+	}
+	return ts.Apply(fmt.Sprintf("Clamp(%f,%f)", min, max), func(v float64) float64 {
+		if math.IsNaN(v) {
+			return v
+		}
+		return math.Max(min, math.Min(max, v))
+	})
+}
+
+// Scale returns a copy where each value is multiplied by factor.
+func (ts *TimeSeries) Scale(factor float64) *TimeSeries {
+	return ts.Apply(fmt.Sprintf("Scale(%f)", factor), func(v float64) float64 {
+		return v * factor
+	})
+}
+
+// Offset returns a copy where delta is added to each value.
+func (ts *TimeSeries) Offset(delta float64) *TimeSeries {
+	return ts.Apply(fmt.Sprintf("Offset(%f)", delta), func(v float64) float64 {
+		return v + delta
+	})
+}
+
+// Replace returns a copy where the values equal to old are set to new. A NaN
+// old matches the NaN values, see ReplaceNaN.
+func (ts *TimeSeries) Replace(old, new float64) *TimeSeries {
+	replaceNaN := math.IsNaN(old)
+	return ts.Apply(fmt.Sprintf("Replace(%f,%f)", old, new), func(v float64) float64 {
+		if v == old || replaceNaN && math.IsNaN(v) {
+			return new
+		}
+		return v
+	})
+}
+
+// ReplaceNaN returns a copy where the NaN values are set to new.
+func (ts *TimeSeries) ReplaceNaN(new float64) *TimeSeries {
+	return ts.Apply(fmt.Sprintf("ReplaceNaN(%f)", new), func(v float64) float64 {
+		if math.IsNaN(v) {
+			return new
+		}
+		return v
+	})
+}
+
+// ReplaceInf returns a copy where the positive and negative infinities are set
+// to new. A NaN new makes them missing values for the statistics.
+func (ts *TimeSeries) ReplaceInf(new float64) *TimeSeries {
+	return ts.Apply(fmt.Sprintf("ReplaceInf(%f)", new), func(v float64) float64 {
+		if math.IsInf(v, 0) {
+			return new
+		}
+		return v
+	})
+}
+
+// Quantize returns a copy where each value is rounded to the nearest multiple
+// of bucketSize. It panics if bucketSize isn't positive.
+func (ts *TimeSeries) Quantize(bucketSize float64) *TimeSeries {
+	if !(bucketSize > 0) {
+		panic(fmt.Sprintf("bucket size %f must be positive", bucketSize))
+	}
+	return ts.Apply(fmt.Sprintf("Quantize(%f)", bucketSize), func(v float64) float64 {
+		return math.Floor(v/bucketSize+0.5) * bucketSize
+	})
+}
+
+// RemoveOutliersIQR returns a copy where the values outside of
+// [Q1 - k*IQR, Q3 + k*IQR] are set to NaN, Q1 and Q3 being the 0.25 and 0.75
+// quantiles of the non NaN values and IQR their difference. A k of 1.5 is the
+// usual choice.
+func (ts *TimeSeries) RemoveOutliersIQR(k float64) *TimeSeries {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	if vals := ts.valid(); len(vals) > 0 {
+		sort.Float64s(vals)
+		q1, q3 := quantile(vals, 0.25), quantile(vals, 0.75)
+		lo, hi = q1-k*(q3-q1), q3+k*(q3-q1)
+	}
+	return ts.Apply(fmt.Sprintf("RemoveOutliersIQR(%f)", k), func(v float64) float64 {
+		if v < lo || v > hi {
+			return math.NaN()
+		}
+		return v
+	})
+}
+
+// GreaterThan returns a mask of the series, 1 where the value is strictly
+// greater than threshold and 0 elsewhere. NaN values stay NaN.
+func (ts *TimeSeries) GreaterThan(threshold float64) *TimeSeries {
+	return ts.mask(fmt.Sprintf("GreaterThan(%f)", threshold), func(v float64) bool {
+		return v > threshold
+	})
+}
+
+// LessThan is like GreaterThan for the values strictly less than threshold.
+func (ts *TimeSeries) LessThan(threshold float64) *TimeSeries {
+	return ts.mask(fmt.Sprintf("LessThan(%f)", threshold), func(v float64) bool {
+		return v < threshold
+	})
+}
+
+// Between is like GreaterThan for the values within [lo, hi]. The bounds are
+// swapped if lo is larger than hi.
+func (ts *TimeSeries) Between(lo, hi float64) *TimeSeries {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return ts.mask(fmt.Sprintf("Between(%f,%f)", lo, hi), func(v float64) bool {
+		return v >= lo && v <= hi
+	})
+}
+
+func (ts *TimeSeries) mask(name string, cond func(float64) bool) *TimeSeries {
+	return ts.Apply(name, func(v float64) float64 {
+		switch {
+		case math.IsNaN(v):
+			return v
+		case cond(v):
+			return 1
+		default:
+			return 0
+		}
+	})
+}
+
+// ZScore returns a copy where the mean is subtracted from each value and the
+// result divided by the standard deviation, both over the finite values. A
+// series with no dispersion is mapped to zero and infinities are kept.
+func (ts *TimeSeries) ZScore() *TimeSeries {
+	mean := ts.MeanFinite()
+	stddev := ts.StdDevFinite()
+	return ts.Apply("ZScore", func(v float64) float64 {
+		if isNotFinite(v) {
+			return v
+		}
+		if stddev == 0 || math.IsNaN(stddev) {
+			return 0
+		}
+		return (v - mean) / stddev
+	})
+}
+
+// MinMaxScale returns a copy where the values are linearly rescaled so that
+// the finite minimum maps to lo and the finite maximum to hi. A constant series
+// is mapped to lo and infinities are kept.
+func (ts *TimeSeries) MinMaxScale(lo, hi float64) *TimeSeries {
+	_, min := ts.MinFinite()
+	_, max := ts.MaxFinite()
+	return ts.Apply(fmt.Sprintf("MinMaxScale(%f,%f)", lo, hi), func(v float64) float64 {
+		if isNotFinite(v) {
+			return v
+		}
+		if max == min {
+			return lo
+		}
+		return lo + (v-min)*(hi-lo)/(max-min)
+	})
+}
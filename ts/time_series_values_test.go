@@ -0,0 +1,301 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesValues(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{-50, 1, NaN, 5, 1000})
+	checkErr(t, err)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ts0.Clamp(0, 10),
+			Exp: &TimeSeries{
+				key:   "Clamp(0.000000,10.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{0, 1, NaN, 5, 10},
+			},
+		},
+		{
+			Got: ts0.Clamp(10, 0),
+			Exp: &TimeSeries{
+				key:   "Clamp(0.000000,10.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{0, 1, NaN, 5, 10},
+			},
+		},
+		{
+			Got: ts0.Replace(1000, NaN),
+			Exp: &TimeSeries{
+				key:   "Replace(1000.000000,NaN)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-50, 1, NaN, 5, NaN},
+			},
+		},
+		{
+			Got: ts0.Replace(NaN, -1),
+			Exp: &TimeSeries{
+				key:   "Replace(NaN,-1.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-50, 1, -1, 5, 1000},
+			},
+		},
+		{
+			Got: ts0.RemoveOutliersIQR(1.5),
+			Exp: &TimeSeries{
+				key:   "RemoveOutliersIQR(1.500000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-50, 1, NaN, 5, NaN},
+			},
+		},
+		{
+			Got: ts0.RemoveOutliersIQR(0),
+			Exp: &TimeSeries{
+				key:   "RemoveOutliersIQR(0.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{NaN, 1, NaN, 5, NaN},
+			},
+		},
+		{
+			Got: ts0.GreaterThan(5),
+			Exp: &TimeSeries{
+				key:   "GreaterThan(5.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{0, 0, NaN, 0, 1},
+			},
+		},
+		{
+			Got: ts0.LessThan(5),
+			Exp: &TimeSeries{
+				key:   "LessThan(5.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{1, 1, NaN, 0, 0},
+			},
+		},
+		{
+			Got: ts0.Between(5, 1),
+			Exp: &TimeSeries{
+				key:   "Between(1.000000,5.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{0, 1, NaN, 1, 0},
+			},
+		},
+		{
+			Got: ts0.ReplaceNaN(0),
+			Exp: &TimeSeries{
+				key:   "ReplaceNaN(0.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-50, 1, 0, 5, 1000},
+			},
+		},
+		{
+			Got: ts0.Quantize(10),
+			Exp: &TimeSeries{
+				key:   "Quantize(10.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-50, 0, NaN, 10, 1000},
+			},
+		},
+		{
+			Got: ts0.Quantize(0.5),
+			Exp: &TimeSeries{
+				key:   "Quantize(0.500000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-50, 1, NaN, 5, 1000},
+			},
+		},
+		{
+			Got: ts0.Scale(0.5),
+			Exp: &TimeSeries{
+				key:   "Scale(0.500000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-25, 0.5, NaN, 2.5, 500},
+			},
+		},
+		{
+			Got: ts0.Offset(-1),
+			Exp: &TimeSeries{
+				key:   "Offset(-1.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-51, 0, NaN, 4, 999},
+			},
+		},
+		{
+			Got: ts0.Scale(9.0 / 5).Offset(32),
+			Exp: &TimeSeries{
+				key:   "Offset(32.000000)(Scale(1.800000)(test0))",
+				start: start,
+				step:  step,
+				data:  []float64{-58, 33.8, NaN, 41, 1832},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
+
+func TestTimeSeriesNormalize(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{2, NaN, 4, 6})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{3, NaN, 3})
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{1, 3, math.Inf(1), 5, math.Inf(-1)})
+	checkErr(t, err)
+
+	ts3, err := NewTimeSeriesOfData("test3", start, step, []float64{3, math.Inf(1)})
+	checkErr(t, err)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ts0.ZScore(),
+			Exp: &TimeSeries{
+				key:   "ZScore(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{-1, NaN, 0, 1},
+			},
+		},
+		{
+			Got: ts1.ZScore(),
+			Exp: &TimeSeries{
+				key:   "ZScore(test1)",
+				start: start,
+				step:  step,
+				data:  []float64{0, NaN, 0},
+			},
+		},
+		{
+			Got: ts0.MinMaxScale(0, 100),
+			Exp: &TimeSeries{
+				key:   "MinMaxScale(0.000000,100.000000)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{0, NaN, 50, 100},
+			},
+		},
+		{
+			Got: ts1.MinMaxScale(1, 2),
+			Exp: &TimeSeries{
+				key:   "MinMaxScale(1.000000,2.000000)(test1)",
+				start: start,
+				step:  step,
+				data:  []float64{1, NaN, 1},
+			},
+		},
+		{
+			Got: ts2.ZScore(),
+			Exp: &TimeSeries{
+				key:   "ZScore(test2)",
+				start: start,
+				step:  step,
+				data:  []float64{-1, 0, math.Inf(1), 1, math.Inf(-1)},
+			},
+		},
+		{
+			Got: ts2.MinMaxScale(0, 100),
+			Exp: &TimeSeries{
+				key:   "MinMaxScale(0.000000,100.000000)(test2)",
+				start: start,
+				step:  step,
+				data:  []float64{0, 50, math.Inf(1), 100, math.Inf(-1)},
+			},
+		},
+		{
+			Got: ts3.ZScore(),
+			Exp: &TimeSeries{
+				key:   "ZScore(test3)",
+				start: start,
+				step:  step,
+				data:  []float64{0, math.Inf(1)},
+			},
+		},
+		{
+			Got: ts3.MinMaxScale(1, 2),
+			Exp: &TimeSeries{
+				key:   "MinMaxScale(1.000000,2.000000)(test3)",
+				start: start,
+				step:  step,
+				data:  []float64{1, math.Inf(1)},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
+
+func TestTimeSeriesReplaceInf(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, math.Inf(1), NaN, math.Inf(-1), 3})
+	checkErr(t, err)
+
+	got := ts0.ReplaceInf(0)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "ReplaceInf(0.000000)(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{1, 0, NaN, 0, 3},
+	})
+
+	checkFloat(t, "mean", ts0.Mean(), NaN)
+	finite := ts0.ReplaceInf(NaN)
+	checkFloat(t, "mean", finite.Mean(), 2)
+	_, max := finite.Max()
+	checkFloat(t, "max", max, 3)
+	_, min := finite.Min()
+	checkFloat(t, "min", min, 1)
+}
+
+func TestTimeSeriesQuantizePanics(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	ts0, err := NewTimeSeriesOfData("test0", start, time.Minute, []float64{1})
+	checkErr(t, err)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("FAIL(bucket): expected panic for a zero bucket size")
+		}
+	}()
+	ts0.Quantize(0)
+}
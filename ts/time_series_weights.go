@@ -0,0 +1,78 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"math"
+	"time"
+)
+
+// WeightAt returns the weight of the point at t, 1 unless set by SetWeightAt.
+func (ts *TimeSeries) WeightAt(t time.Time) (float64, bool) {
+	index := ts.index(t)
+	if index == -1 {
+		return math.NaN(), false
+	}
+	if ts.weights == nil {
+		return 1, true
+	}
+	return ts.weights[index], true
+}
+
+// SetWeightAt sets the weight of the point at t. The other points weigh 1
+// until set, including the ones added when extending the series.
+func (ts *TimeSeries) SetWeightAt(t time.Time, weight float64) bool {
+	index := ts.index(t)
+	if index == -1 {
+		return false
+	}
+	if ts.weights == nil {
+		ts.weights = make([]float64, 0, len(ts.data))
+	}
+	ts.padWeights()
+	ts.weights[index] = weight
+	return true
+}
+
+// WeightedMean returns the mean of the non NaN values weighted by their
+// weights, which is the Mean of a series without weights. NaN is returned if
+// there are no such values or their weights sum to zero.
+func (ts *TimeSeries) WeightedMean() float64 {
+	if ts.weights == nil {
+		return ts.Mean()
+	}
+
+	var sum, weights float64
+	for i, v := range ts.data {
+		w := ts.weights[i]
+		if math.IsNaN(v) || math.IsNaN(w) {
+			continue
+		}
+		sum += w * v
+		weights += w
+	}
+	if weights == 0 {
+		return math.NaN()
+	}
+	return sum / weights
+}
+
+// padWeights gives a weight of 1 to the points added since the weights were
+// last set.
+func (ts *TimeSeries) padWeights() {
+	if ts.weights == nil {
+		return
+	}
+	for len(ts.weights) < len(ts.data) {
+		ts.weights = append(ts.weights, 1)
+	}
+}
+
+func copyWeights(weights []float64) []float64 {
+	if weights == nil {
+		return nil
+	}
+	cp := make([]float64, len(weights))
+	copy(cp, weights)
+	return cp
+}
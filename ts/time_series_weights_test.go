@@ -0,0 +1,106 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesWeights(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, NaN, 4})
+	checkErr(t, err)
+
+	checkFloat(t, "unweighted", ts0.WeightedMean(), ts0.Mean())
+	if w, ok := ts0.WeightAt(start); !ok || w != 1 {
+		t.Errorf("FAIL(WeightAt): got: '%f', '%v', expected '%f'", w, ok, 1.0)
+	}
+
+	if !ts0.SetWeightAt(start.Add(3*step), 4) {
+		t.Errorf("FAIL(SetWeightAt): expected the point to be set")
+	}
+	if ts0.SetWeightAt(start.Add(time.Hour), 4) {
+		t.Errorf("FAIL(SetWeightAt): expected no point outside the series")
+	}
+	checkFloat(t, "weighted", ts0.WeightedMean(), (1+2+16)/6.0)
+	checkFloat(t, "mean", ts0.Mean(), 7/3.0)
+
+	ts0.ExtendWith(10)
+	if w, ok := ts0.WeightAt(start.Add(4 * step)); !ok || w != 1 {
+		t.Errorf("FAIL(WeightAt): got: '%f', '%v', expected '%f'", w, ok, 1.0)
+	}
+	checkErr(t, ts0.Verify())
+	checkFloat(t, "extended", ts0.WeightedMean(), (1+2+16+10)/7.0)
+
+	cp := ts0.Copy()
+	cp.SetWeightAt(start, 0)
+	checkFloat(t, "copy", ts0.WeightedMean(), (1+2+16+10)/7.0)
+
+	reversed := ts0.Reverse()
+	if w, _ := reversed.WeightAt(start.Add(step)); w != 4 {
+		t.Errorf("FAIL(Reverse): got: '%f', expected '%f'", w, 4.0)
+	}
+
+	sliced, err := ts0.Slice(start.Add(3*step), ts0.End())
+	checkErr(t, err)
+	checkErr(t, sliced.Verify())
+	checkFloat(t, "sliced", sliced.WeightedMean(), (16+10)/5.0)
+}
+
+func TestTimeSeriesWeightsEncoding(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, NaN})
+	checkErr(t, err)
+	ts0.SetWeightAt(start.Add(step), 3)
+	ts0.SetWeightAt(start.Add(2*step), NaN)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{1, 2})
+	checkErr(t, err)
+
+	roundTrips := map[string]func(*TimeSeries) (*TimeSeries, error){
+		"json": func(ts *TimeSeries) (*TimeSeries, error) {
+			body, err := json.Marshal(ts)
+			if err != nil {
+				return nil, err
+			}
+			got := &TimeSeries{}
+			return got, json.Unmarshal(body, got)
+		},
+		"gob": func(ts *TimeSeries) (*TimeSeries, error) {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(ts); err != nil {
+				return nil, err
+			}
+			got := &TimeSeries{}
+			return got, gob.NewDecoder(&buf).Decode(got)
+		},
+		"binary": func(ts *TimeSeries) (*TimeSeries, error) {
+			var buf bytes.Buffer
+			if err := ts.WriteBinary(&buf); err != nil {
+				return nil, err
+			}
+			return ReadBinary(&buf)
+		},
+	}
+
+	for name, roundTrip := range roundTrips {
+		got, err := roundTrip(ts0)
+		checkErr(t, err)
+		checkFloat(t, name, got.WeightedMean(), ts0.WeightedMean())
+		checkData(t, got.weights, ts0.weights)
+
+		got, err = roundTrip(ts1)
+		checkErr(t, err)
+		if got.weights != nil {
+			t.Errorf("FAIL(%s): got: '%v', expected no weights", name, got.weights)
+		}
+	}
+}
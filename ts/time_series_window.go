@@ -0,0 +1,232 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// windowLength returns the number of points covered by a trailing window.
+func (ts *TimeSeries) windowLength(window time.Duration) (int, error) {
+	if window < ts.step {
+		return 0, fmt.Errorf("window %v can't be smaller than step %v", window, ts.step)
+	}
+	return int(window / ts.step), nil
+}
+
+// RollingMean returns a series where each point is the mean of the points
+// within the trailing window, current point included. NaN values are skipped
+// and a window that only holds NaN values yields NaN. Each window is summed on
+// its own so that an infinity or a large value doesn't leak into the windows
+// after it.
+func (ts *TimeSeries) RollingMean(window time.Duration) (*TimeSeries, error) {
+	n, err := ts.windowLength(window)
+	if err != nil {
+		return nil, err
+	}
+
+	rts := ts.Copy()
+	rts.key = fmt.Sprintf("RollingMean(%v)(%s)", window, ts.key)
+
+	for i := range ts.data {
+		var sum float64
+		var count int
+		for j := i; j >= 0 && j > i-n; j-- {
+			if v := ts.data[j]; !math.IsNaN(v) {
+				sum += v
+				count++
+			}
+		}
+		if count == 0 {
+			rts.data[i] = math.NaN()
+			continue
+		}
+		rts.data[i] = sum / float64(count)
+	}
+	return rts, nil
+}
+
+// RollingStdDev returns a series where each point is the sample standard
+// deviation of the non NaN values within the trailing window, current point
+// included. A window with less than two such values yields NaN.
+func (ts *TimeSeries) RollingStdDev(window time.Duration) (*TimeSeries, error) {
+	n, err := ts.windowLength(window)
+	if err != nil {
+		return nil, err
+	}
+
+	rts := ts.Copy()
+	rts.key = fmt.Sprintf("RollingStdDev(%v)(%s)", window, ts.key)
+
+	vals := make([]float64, 0, n)
+	for i := range ts.data {
+		vals = vals[:0]
+		for j := i; j >= 0 && j > i-n; j-- {
+			if v := ts.data[j]; !math.IsNaN(v) {
+				vals = append(vals, v)
+			}
+		}
+		rts.data[i] = math.Sqrt(variance(vals))
+	}
+	return rts, nil
+}
+
+// RollingMedian returns a series where each point is the median of the non NaN
+// values within the trailing window, current point included, and NaN if there
+// are none. The window is kept sorted by binary insertion and removal, which
+// costs O(log w) comparisons and O(w) moves per point for w points per window.
+func (ts *TimeSeries) RollingMedian(window time.Duration) (*TimeSeries, error) {
+	n, err := ts.windowLength(window)
+	if err != nil {
+		return nil, err
+	}
+
+	rts := ts.Copy()
+	rts.key = fmt.Sprintf("RollingMedian(%v)(%s)", window, ts.key)
+
+	sorted := make([]float64, 0, n+1)
+	for i, v := range ts.data {
+		if !math.IsNaN(v) {
+			j := sort.SearchFloat64s(sorted, v)
+			sorted = append(sorted, 0)
+			copy(sorted[j+1:], sorted[j:])
+			sorted[j] = v
+		}
+		if i >= n {
+			if old := ts.data[i-n]; !math.IsNaN(old) {
+				j := sort.SearchFloat64s(sorted, old)
+				sorted = append(sorted[:j], sorted[j+1:]...)
+			}
+		}
+
+		if len(sorted) == 0 {
+			rts.data[i] = math.NaN()
+			continue
+		}
+		rts.data[i] = quantile(sorted, 0.5)
+	}
+	return rts, nil
+}
+
+// AnomaliesZScore returns the times of the points deviating from the mean of
+// the preceding window by more than threshold standard deviations. Points
+// whose preceding window holds less than two valid values are skipped, as are
+// all points if the window is smaller than the step.
+func (ts *TimeSeries) AnomaliesZScore(window time.Duration, threshold float64) []time.Time {
+	means, err := ts.RollingMean(window)
+	if err != nil {
+		return nil
+	}
+	stddevs, err := ts.RollingStdDev(window)
+	if err != nil {
+		return nil
+	}
+
+	anomalies := []time.Time{}
+	for i := 1; i < len(ts.data); i++ {
+		v, mean, stddev := ts.data[i], means.data[i-1], stddevs.data[i-1]
+		if math.IsNaN(v) || math.IsNaN(stddev) {
+			continue
+		}
+		if math.Abs(v-mean) > threshold*stddev {
+			anomalies = append(anomalies, ts.TimeAt(i))
+		}
+	}
+	return anomalies
+}
+
+// EMA returns the exponential moving average of the series with 0 < alpha <= 1,
+// seeded from the first non NaN value. Points before the seed are NaN and NaN
+// values afterwards hold the previous average.
+func (ts *TimeSeries) EMA(alpha float64) (*TimeSeries, error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, fmt.Errorf("alpha %f is not within (0, 1]", alpha)
+	}
+
+	ets := ts.Copy()
+	ets.key = fmt.Sprintf("EMA(%f)(%s)", alpha, ts.key)
+
+	ema := math.NaN()
+	for i, v := range ts.data {
+		switch {
+		case math.IsNaN(v):
+		case math.IsNaN(ema):
+			ema = v
+		default:
+			ema = alpha*v + (1-alpha)*ema
+		}
+		ets.data[i] = ema
+	}
+	return ets, nil
+}
+
+// WMA returns the linearly weighted moving average over the trailing window of
+// points, the current point weighing window and the oldest one 1. The weights
+// are normalized over the non NaN values, a window without any yielding NaN.
+func (ts *TimeSeries) WMA(window int) (*TimeSeries, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("window %d must be at least 1", window)
+	}
+
+	wts := ts.Copy()
+	wts.key = fmt.Sprintf("WMA(%d)(%s)", window, ts.key)
+
+	for i := range ts.data {
+		var sum, weights float64
+		for j := 0; j < window && j <= i; j++ {
+			v := ts.data[i-j]
+			if math.IsNaN(v) {
+				continue
+			}
+			weight := float64(window - j)
+			sum += weight * v
+			weights += weight
+		}
+
+		if weights == 0 {
+			wts.data[i] = math.NaN()
+			continue
+		}
+		wts.data[i] = sum / weights
+	}
+	return wts, nil
+}
+
+// WindowIterator returns an iterator over the trailing windows of the series,
+// one per point.
+func (ts *TimeSeries) WindowIterator(window time.Duration) (*WindowIterator, error) {
+	n, err := ts.windowLength(window)
+	if err != nil {
+		return nil, err
+	}
+	return &WindowIterator{length: n, series: ts}, nil
+}
+
+type WindowIterator struct {
+	index  int
+	length int
+	series *TimeSeries
+}
+
+// Next returns a copy of the values of the next trailing window, NaN included,
+// and its end which is exclusive as for End. The first windows are shorter as
+// they are bounded by the start of the series.
+func (it *WindowIterator) Next() (end time.Time, vals []float64, ok bool) {
+	ts := it.series
+	if it.index >= len(ts.data) {
+		return time.Time{}, nil, false
+	}
+
+	from := it.index + 1 - it.length
+	if from < 0 {
+		from = 0
+	}
+	vals = make([]float64, it.index+1-from)
+	copy(vals, ts.data[from:it.index+1])
+
+	it.index++
+	return ts.TimeAt(it.index), vals, true
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesRollingMean(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3, 4, 5})
+	checkErr(t, err)
+
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{1, NaN, 3, NaN, NaN, NaN, 7})
+	checkErr(t, err)
+
+	mean0, err := ts0.RollingMean(2 * time.Minute)
+	checkErr(t, err)
+
+	mean1, err := ts1.RollingMean(3 * time.Minute)
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfData("test2", start, step, []float64{1, math.Inf(1), 3, 4, 5, 6})
+	checkErr(t, err)
+	mean2, err := ts2.RollingMean(2 * time.Minute)
+	checkErr(t, err)
+
+	ts3, err := NewTimeSeriesOfData("test3", start, step, []float64{1e20, 1, 2, 3})
+	checkErr(t, err)
+	mean3, err := ts3.RollingMean(2 * time.Minute)
+	checkErr(t, err)
+
+	if _, err := ts0.RollingMean(time.Second); err == nil {
+		t.Errorf("FAIL(window): expected error for window smaller than step")
+	}
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: mean0,
+			Exp: &TimeSeries{
+				key:   "RollingMean(2m0s)(test0)",
+				start: start,
+				step:  step,
+				data:  []float64{1, 1.5, 2.5, 3.5, 4.5},
+			},
+		},
+		{
+			Got: mean1,
+			Exp: &TimeSeries{
+				key:   "RollingMean(3m0s)(test1)",
+				start: start,
+				step:  step,
+				data:  []float64{1, 1, 2, 3, 3, NaN, 7},
+			},
+		},
+		{
+			Got: mean2,
+			Exp: &TimeSeries{
+				key:   "RollingMean(2m0s)(test2)",
+				start: start,
+				step:  step,
+				data:  []float64{1, math.Inf(1), math.Inf(1), 3.5, 4.5, 5.5},
+			},
+		},
+		{
+			Got: mean3,
+			Exp: &TimeSeries{
+				key:   "RollingMean(2m0s)(test3)",
+				start: start,
+				step:  step,
+				data:  []float64{1e20, 5e19, 1.5, 2.5},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
+
+func TestTimeSeriesEMA(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{NaN, 4, 8, NaN, 0})
+	checkErr(t, err)
+
+	got, err := ts0.EMA(0.5)
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "EMA(0.500000)(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{NaN, 4, 6, 6, 3},
+	})
+
+	for _, alpha := range []float64{0, -1, 1.5, NaN} {
+		if _, err := ts0.EMA(alpha); err == nil {
+			t.Errorf("FAIL(alpha): expected error for alpha '%f'", alpha)
+		}
+	}
+}
+
+func TestTimeSeriesWMA(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{NaN, 3, 6, NaN, 9})
+	checkErr(t, err)
+
+	got, err := ts0.WMA(3)
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "WMA(3)(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{NaN, 3, 4.8, 5, 8.25},
+	})
+
+	got, err = ts0.WMA(1)
+	checkErr(t, err)
+	checkData(t, got.data, ts0.data)
+
+	if _, err := ts0.WMA(0); err == nil {
+		t.Errorf("FAIL(window): expected error for window '%d'", 0)
+	}
+}
+
+func TestTimeSeriesRollingStdDev(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{2, 4, NaN, 4, 10, 10})
+	checkErr(t, err)
+
+	got, err := ts0.RollingStdDev(3 * step)
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "RollingStdDev(3m0s)(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{NaN, math.Sqrt2, math.Sqrt2, 0, math.Sqrt(18), math.Sqrt(12)},
+	})
+
+	if _, err := ts0.RollingStdDev(time.Second); err == nil {
+		t.Errorf("FAIL(window): expected error for a window smaller than the step")
+	}
+}
+
+func TestTimeSeriesRollingMedian(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 100, 3, NaN, 2, 2, NaN, NaN, NaN})
+	checkErr(t, err)
+
+	got, err := ts0.RollingMedian(3 * step)
+	checkErr(t, err)
+	checkTimeSeries(t, got, &TimeSeries{
+		key:   "RollingMedian(3m0s)(test0)",
+		start: start,
+		step:  step,
+		data:  []float64{1, 50.5, 3, 51.5, 2.5, 2, 2, 2, NaN},
+	})
+
+	inf := math.Inf(1)
+	ts1, err := NewTimeSeriesOfData("test1", start, step, []float64{inf, inf, 1, 2})
+	checkErr(t, err)
+	got, err = ts1.RollingMedian(3 * step)
+	checkErr(t, err)
+	checkData(t, got.data, []float64{inf, inf, inf, 2})
+
+	if _, err := ts0.RollingMedian(time.Second); err == nil {
+		t.Errorf("FAIL(window): expected error for a window smaller than the step")
+	}
+}
+
+func TestTimeSeriesAnomaliesZScore(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{50, 10, 11, 9, 10, 30, 10, NaN, 9, 11, -20})
+	checkErr(t, err)
+
+	got := ts0.AnomaliesZScore(4*step, 3)
+	exp := []time.Time{start.Add(5 * step), start.Add(10 * step)}
+	if len(got) != len(exp) {
+		t.Fatalf("FAIL(anomalies): got: '%v', expected '%v'", got, exp)
+	}
+	for i := range exp {
+		checkTime(t, fmt.Sprintf("anomaly %d", i), got[i], exp[i])
+	}
+
+	if got := ts0.AnomaliesZScore(time.Second, 3); len(got) != 0 {
+		t.Errorf("FAIL(window): got: '%v', expected no anomalies", got)
+	}
+}
+
+func TestTimeSeriesWindowIterator(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, NaN, 4})
+	checkErr(t, err)
+
+	exp := [][]float64{{1}, {1, 2}, {1, 2, NaN}, {2, NaN, 4}}
+
+	it, err := ts0.WindowIterator(3 * step)
+	checkErr(t, err)
+	var i int
+	for end, vals, ok := it.Next(); ok; end, vals, ok = it.Next() {
+		checkTime(t, fmt.Sprintf("end %d", i), end, start.Add(time.Duration(i+1)*step))
+		checkData(t, vals, exp[i])
+		i++
+	}
+	if i != len(exp) {
+		t.Errorf("FAIL(windows): got: '%d', expected '%d'", i, len(exp))
+	}
+
+	if _, err := ts0.WindowIterator(time.Second); err == nil {
+		t.Errorf("FAIL(window): expected error for a window smaller than the step")
+	}
+}
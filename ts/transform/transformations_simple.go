@@ -123,38 +123,27 @@ func (mark *MarkEqual) Transform(val float64) float64 {
 	return 0.0
 }
 
+// DiffPrevious returns the difference with the previous value, the first
+// value has no predecessor and is NaN.
 type DiffPrevious struct {
 	count int64
 	last  float64
 }
 
 func (diff *DiffPrevious) Name() string {
-	return "MarkRaise"
+	return "DiffPrevious"
 }
 
 func (diff *DiffPrevious) Transform(val float64) float64 {
-	panic("not finished")
-
-	if math.IsNaN(val) {
-		return val
-	}
-
 	if diff.count == 0 {
 		diff.count++
 		diff.last = val
-		return 0.0
+		return math.NaN()
 	}
 	diff.count++
-	if diff.last == val {
-		diff.last = val
-		return 0.0
-	}
-	if diff.last-val < 0.0 {
-		diff.last = val
-		return 1.0
-	}
+	delta := val - diff.last
 	diff.last = val
-	return 0.0
+	return delta
 }
 
 type Transforms []ts.Transform
@@ -219,6 +219,16 @@ func TestTransforms(t *testing.T) {
 				Data:  []float64{NaN, 0, 0, 0, 1, NaN, 1, 0},
 			},
 		},
+		{
+			Got: tsRaise.Transform(&DiffPrevious{}),
+			Exp: &TestSeries{
+				Key:   "DiffPrevious(tsRaise)",
+				Start: start,
+				End:   start.Add(8 * step),
+				Step:  step,
+				Data:  []float64{NaN, NaN, 0, -1, 1, NaN, NaN, 0},
+			},
+		},
 	}
 
 	for _, pair := range tss {